@@ -0,0 +1,8 @@
+# Example Usage
+data "viettelidc_availability_zones" "hcmc" {
+  region = "hcmc"
+}
+
+output "zone_codes" {
+  value = data.viettelidc_availability_zones.hcmc.items[*].code
+}
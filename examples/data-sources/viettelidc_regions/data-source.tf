@@ -0,0 +1,6 @@
+# Example Usage
+data "viettelidc_regions" "all" {}
+
+output "region_codes" {
+  value = data.viettelidc_regions.all.items[*].code
+}
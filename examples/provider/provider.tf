@@ -5,3 +5,52 @@ provider "viettelidc" {
   password  = "Vtdc@12345"
   mfa_code  = var.mfa_code
 }
+
+# Access token authentication
+provider "viettelidc" {
+  access_token = var.access_token
+}
+
+# Unattended authentication with a MFA device secret
+provider "viettelidc" {
+  domain_id       = "3b3e6994-4b04-40ea-bedc-5befd874d73a"
+  username        = "iac"
+  password        = var.password
+  mfa_totp_secret = var.mfa_totp_secret
+}
+
+# Shared credentials file authentication
+provider "viettelidc" {
+  profile = "staging"
+}
+
+# Staging gateway with a local vOKS mock server
+provider "viettelidc" {
+  host = "https://api-staging.viettelidc.com.vn"
+
+  endpoints {
+    voks = "http://localhost:8080"
+  }
+}
+
+# Corporate network with a TLS inspecting proxy
+provider "viettelidc" {
+  http_proxy = "http://proxy.example.internal:3128"
+  ca_bundle  = "/etc/ssl/certs/corporate-ca.pem"
+}
+
+# Regions, declared from the API gateways shown in the ViettelIdc portal
+provider "viettelidc" {
+  region = "hanoi"
+
+  regions = {
+    hanoi = {
+      endpoint = var.hanoi_endpoint
+      host_id  = var.hanoi_host_id
+    }
+    hcmc = {
+      endpoint = var.hcmc_endpoint
+      host_id  = var.hcmc_host_id
+    }
+  }
+}
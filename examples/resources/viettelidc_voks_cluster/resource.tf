@@ -1,10 +1,13 @@
 # Example Usage - with cluster
 resource "viettelidc_voks_cluster" "example" {
-  name    = "k8s-cluster"
-  version = "1.8.0"
+  name                        = "k8s-cluster"
+  version                     = "v1.30.5"
+  control_plane_resource_type = "T1.vOKS 2"
 
   vpc_config {
-    vpc_id = "0106"
+    vpc_id             = 19178
+    subnet_ids         = [7281, 9182]
+    security_group_ids = [3153]
   }
 }
 
@@ -12,10 +15,10 @@ resource "viettelidc_voks_cluster" "example" {
 # Example Usage - with NFS
 resource "viettelidc_voks_cluster" "example" {
   name    = "k8s-cluster"
-  version = "1.8.0"
+  version = "v1.30.5"
 
   vpc_config {
-    vpc_id = "234134"
+    vpc_id = 19178
   }
 
   nfs = {
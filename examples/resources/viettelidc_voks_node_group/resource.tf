@@ -91,4 +91,23 @@ resource "viettelidc_voks_node_group" "example" {
     value  = "gpu"
     effect = "NoSchedule"
   }
-}
\ No newline at end of file
+}
+
+# Example Usage - with timeouts
+resource "viettelidc_voks_node_group" "example" {
+  cluster_id    = 123
+  name          = "k8s-node-group"
+  resource_type = "T1.vOKS 1"
+
+  scaling_config {
+    enable_auto_scale = true
+    min_node          = 1
+    max_node          = 2
+  }
+
+  timeouts {
+    create = "45m"
+    update = "20m"
+    delete = "20m"
+  }
+}
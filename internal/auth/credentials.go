@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package auth
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultProfile is the profile used when none is configured.
+const DefaultProfile = "default"
+
+// ErrProfileNotFound is returned when the shared credentials file does not
+// contain the requested profile.
+var ErrProfileNotFound = errors.New("profile not found")
+
+// Profile is a named section of the shared credentials file, for example:
+//
+//	[staging]
+//	domain_id       = 3b3e6994-4b04-40ea-bedc-5befd874d73a
+//	username        = iac
+//	password        = secret
+//	mfa_totp_secret = JBSWY3DPEHPK3PXP
+//	host            = https://api-staging.viettelidc.com.vn
+//	voks_endpoint   = http://localhost:8080
+type Profile struct {
+	Name          string
+	Host          string
+	Region        string
+	DomainId      string
+	Username      string
+	Password      string
+	MfaTotpSecret string
+	AccessToken   string
+	IamEndpoint   string
+	VoksEndpoint  string
+	VpcEndpoint   string
+}
+
+// DefaultCredentialsFile returns the location of the shared credentials file,
+// ~/.viettelidc/credentials.
+func DefaultCredentialsFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".viettelidc", "credentials"), nil
+}
+
+// LoadProfile reads the named profile from the shared credentials file. It
+// returns an error wrapping fs.ErrNotExist when the file does not exist and
+// ErrProfileNotFound when the file has no section for the profile.
+func LoadProfile(filename, name string) (*Profile, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var (
+		profile *Profile
+		section string
+		lineNo  int
+	)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("%s:%d: invalid section header %q", filename, lineNo, line)
+			}
+			section = strings.TrimSpace(strings.TrimPrefix(line[1:len(line)-1], "profile "))
+			if section == name && profile == nil {
+				profile = &Profile{Name: name}
+			}
+			continue
+		}
+
+		if section != name {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected key = value, got %q", filename, lineNo, line)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		switch key {
+		case "host":
+			profile.Host = value
+		case "region":
+			profile.Region = value
+		case "domain_id":
+			profile.DomainId = value
+		case "username":
+			profile.Username = value
+		case "password":
+			profile.Password = value
+		case "mfa_totp_secret":
+			profile.MfaTotpSecret = value
+		case "access_token":
+			profile.AccessToken = value
+		case "iam_endpoint":
+			profile.IamEndpoint = value
+		case "voks_endpoint":
+			profile.VoksEndpoint = value
+		case "vpc_endpoint":
+			profile.VpcEndpoint = value
+		default:
+			return nil, fmt.Errorf("%s:%d: unknown key %q in profile %q", filename, lineNo, key, name)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if profile == nil {
+		return nil, fmt.Errorf("%w: %q in %s", ErrProfileNotFound, name, filename)
+	}
+
+	return profile, nil
+}
+
+// IsNotExist reports whether err was caused by a missing credentials file.
+func IsNotExist(err error) bool {
+	return errors.Is(err, fs.ErrNotExist)
+}
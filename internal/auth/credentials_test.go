@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package auth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testCredentialsFile = `
+# Shared by the whole team
+[default]
+domain_id = 3b3e6994-4b04-40ea-bedc-5befd874d73a
+username  = iac
+password  = "Vtdc@12345"
+region    = hcmc
+
+[profile staging]
+domain_id       = 9e9480cc-96aa-446e-b08b-5cd7b2f438ab
+username        = iac-staging
+password        = secret
+mfa_totp_secret = JBSWY3DPEHPK3PXP
+host            = https://api-staging.viettelidc.com.vn
+voks_endpoint   = http://localhost:8080
+`
+
+func writeCredentialsFile(t *testing.T, content string) string {
+	t.Helper()
+
+	filename := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestLoadProfile(t *testing.T) {
+	filename := writeCredentialsFile(t, testCredentialsFile)
+
+	testCases := map[string]Profile{
+		"default": {
+			Name:     "default",
+			DomainId: "3b3e6994-4b04-40ea-bedc-5befd874d73a",
+			Username: "iac",
+			Password: "Vtdc@12345",
+			Region:   "hcmc",
+		},
+		"staging": {
+			Name:          "staging",
+			DomainId:      "9e9480cc-96aa-446e-b08b-5cd7b2f438ab",
+			Username:      "iac-staging",
+			Password:      "secret",
+			MfaTotpSecret: "JBSWY3DPEHPK3PXP",
+			Host:          "https://api-staging.viettelidc.com.vn",
+			VoksEndpoint:  "http://localhost:8080",
+		},
+	}
+
+	for name, want := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := LoadProfile(filename, name)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if *got != want {
+				t.Errorf("expected %+v, got %+v", want, *got)
+			}
+		})
+	}
+}
+
+func TestLoadProfile_Errors(t *testing.T) {
+	filename := writeCredentialsFile(t, testCredentialsFile)
+
+	if _, err := LoadProfile(filename, "production"); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("expected ErrProfileNotFound, got %v", err)
+	}
+
+	if _, err := LoadProfile(filepath.Join(t.TempDir(), "missing"), DefaultProfile); !IsNotExist(err) {
+		t.Errorf("expected not exist error, got %v", err)
+	}
+
+	invalid := writeCredentialsFile(t, "[default]\nusername_typo = iac\n")
+	if _, err := LoadProfile(invalid, DefaultProfile); err == nil {
+		t.Error("expected error for unknown key")
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/viettelidc-provider/viettelidc-api-client-go/service/iam"
+)
+
+var (
+	// ErrMfaCodeRequired is returned when the account requires a second
+	// authentication step and neither a MFA code nor a TOTP secret is set.
+	ErrMfaCodeRequired = errors.New("account requires a MFA code")
+	// ErrInvalidTOTPSecret is returned when no MFA code can be generated
+	// from the TOTP secret.
+	ErrInvalidTOTPSecret = errors.New("invalid TOTP secret")
+)
+
+// Credentials are exchanged for an access token by Login.
+type Credentials struct {
+	DomainId      string
+	Username      string
+	Password      string
+	MfaCode       string
+	MfaTotpSecret string
+}
+
+// Login exchanges the username and password, and the MFA code when the
+// account requires a second authentication step, for an access token. When a
+// TOTP secret is set the MFA code is generated right before it is verified,
+// otherwise the static MFA code is used.
+func Login(ctx context.Context, client *iam.APIClient, credentials Credentials) (string, error) {
+	loginRes, _, err := client.AuthorizationControllerApi.LoginViaLoginPage(ctx, iam.LoginViaLoginPageRequest{
+		Username:     credentials.Username,
+		Password:     credentials.Password,
+		DomainId:     credentials.DomainId,
+		IsRememberMe: false,
+		UserType:     "IAM_USER",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if !loginRes.IsRequiredSecondAuthenticationStep {
+		return loginRes.Data, nil
+	}
+
+	mfaCode := credentials.MfaCode
+	if credentials.MfaTotpSecret != "" {
+		mfaCode, err = GenerateTOTP(credentials.MfaTotpSecret, time.Now())
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", ErrInvalidTOTPSecret, err)
+		}
+	}
+
+	if mfaCode == "" {
+		return "", ErrMfaCodeRequired
+	}
+
+	exchangeTokenRes, _, err := client.AuthorizationControllerApi.VerifyMfaTokenCode(ctx, iam.LoginViaPageWithMfaCodeRequest{
+		MfaToken: loginRes.Data,
+		MfaCode:  mfaCode,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return exchangeTokenRes.Data, nil
+}
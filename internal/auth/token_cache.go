@@ -0,0 +1,166 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	// tokenCacheTTL is how long a token is cached when its expiry cannot be
+	// read from the token itself.
+	tokenCacheTTL = 30 * time.Minute
+	// tokenExpirySkew discards cached tokens shortly before they expire, so
+	// they do not expire in the middle of a request.
+	tokenExpirySkew = time.Minute
+)
+
+// TokenCache persists access tokens on disk, so that the provider processes
+// Terraform starts for plan, apply and refresh share a single login. Entries
+// are keyed by the IAM endpoint issuing them, domain and username, and
+// readable by the owner only.
+type TokenCache struct {
+	dir string
+}
+
+type cachedToken struct {
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// DefaultTokenCacheDir returns the default token cache location,
+// ~/.viettelidc/cache.
+func DefaultTokenCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".viettelidc", "cache"), nil
+}
+
+// NewTokenCache returns a token cache stored in dir.
+func NewTokenCache(dir string) *TokenCache {
+	return &TokenCache{dir: dir}
+}
+
+// Get returns the cached access token, if it exists and has not expired.
+func (c *TokenCache) Get(host, domainId, username string) (string, bool) {
+	filename := c.filename(host, domainId, username)
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return "", false
+	}
+
+	// Never trust a token that other users could have read or planted.
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return "", false
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return "", false
+	}
+
+	var entry cachedToken
+	if err := json.Unmarshal(content, &entry); err != nil || entry.AccessToken == "" {
+		return "", false
+	}
+
+	if time.Now().Add(tokenExpirySkew).After(entry.ExpiresAt) {
+		return "", false
+	}
+
+	return entry.AccessToken, true
+}
+
+// Put stores the access token in the cache.
+func (c *TokenCache) Put(host, domainId, username, token string) error {
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+
+	expiresAt, ok := TokenExpiry(token)
+	if !ok {
+		expiresAt = time.Now().Add(tokenCacheTTL)
+	}
+
+	content, err := json.Marshal(cachedToken{
+		AccessToken: token,
+		ExpiresAt:   expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so that concurrent provider
+	// processes never read a partially written entry.
+	file, err := os.CreateTemp(c.dir, ".token-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if err := file.Chmod(0o600); err != nil && runtime.GOOS != "windows" {
+		file.Close()
+		return err
+	}
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), c.filename(host, domainId, username))
+}
+
+// Delete removes the cached access token, for example after the API has
+// rejected it.
+func (c *TokenCache) Delete(host, domainId, username string) error {
+	err := os.Remove(c.filename(host, domainId, username))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (c *TokenCache) filename(host, domainId, username string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{host, domainId, username}, "\x00")))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// TokenExpiry returns the expiry of a JWT access token from its exp claim.
+// The signature is not verified, the token is only inspected to know when
+// it has to be renewed.
+func TokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		ExpiresAt int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ExpiresAt == 0 {
+		return time.Time{}, false
+	}
+
+	return time.Unix(claims.ExpiresAt, 0), true
+}
@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package auth
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func testJWT(expiresAt time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":"iac","exp":%d}`, expiresAt.Unix())))
+	return "eyJhbGciOiJIUzI1NiJ9." + payload + ".c2lnbmF0dXJl"
+}
+
+func TestTokenCache(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	cache := NewTokenCache(dir)
+
+	if _, ok := cache.Get("https://api.viettelidc.com.vn", "domain", "iac"); ok {
+		t.Fatal("expected empty cache")
+	}
+
+	token := testJWT(time.Now().Add(time.Hour))
+	if err := cache.Put("https://api.viettelidc.com.vn", "domain", "iac", token); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	got, ok := cache.Get("https://api.viettelidc.com.vn", "domain", "iac")
+	if !ok || got != token {
+		t.Errorf("expected cached token %q, got %q", token, got)
+	}
+
+	// Entries are keyed by host, domain and username.
+	if _, ok := cache.Get("https://api.viettelidc.com.vn", "domain", "other"); ok {
+		t.Error("expected cache miss for another username")
+	}
+	if _, ok := cache.Get("http://localhost:8080", "domain", "iac"); ok {
+		t.Error("expected cache miss for another host")
+	}
+
+	if runtime.GOOS != "windows" {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if perm := info.Mode().Perm(); perm != 0o600 {
+				t.Errorf("expected %s to have permissions 0600, got %o", entry.Name(), perm)
+			}
+		}
+	}
+
+	if err := cache.Delete("https://api.viettelidc.com.vn", "domain", "iac"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := cache.Get("https://api.viettelidc.com.vn", "domain", "iac"); ok {
+		t.Error("expected cache miss after delete")
+	}
+}
+
+func TestTokenCache_Expired(t *testing.T) {
+	cache := NewTokenCache(t.TempDir())
+
+	if err := cache.Put("host", "domain", "iac", testJWT(time.Now().Add(30*time.Second))); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, ok := cache.Get("host", "domain", "iac"); ok {
+		t.Error("expected token expiring within the skew to be discarded")
+	}
+}
+
+func TestTokenExpiry(t *testing.T) {
+	expiresAt := time.Unix(1893456000, 0)
+
+	got, ok := TokenExpiry(testJWT(expiresAt))
+	if !ok || !got.Equal(expiresAt) {
+		t.Errorf("expected %s, got %s", expiresAt, got)
+	}
+
+	if _, ok := TokenExpiry("opaque-token"); ok {
+		t.Error("expected no expiry for an opaque token")
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGenerateTOTP(t *testing.T) {
+	// Base32 encoding of the RFC 6238 appendix B SHA1 seed "12345678901234567890".
+	const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	testCases := map[string]struct {
+		secret string
+		time   time.Time
+		want   string
+	}{
+		"rfc6238-59": {
+			secret: secret,
+			time:   time.Unix(59, 0),
+			want:   "287082",
+		},
+		"rfc6238-1111111109": {
+			secret: secret,
+			time:   time.Unix(1111111109, 0),
+			want:   "081804",
+		},
+		"rfc6238-2000000000": {
+			secret: secret,
+			time:   time.Unix(2000000000, 0),
+			want:   "279037",
+		},
+		"grouped-lowercase": {
+			secret: "gezd gnbv gy3t qojq gezd gnbv gy3t qojq",
+			time:   time.Unix(59, 0),
+			want:   "287082",
+		},
+		"otpauth-uri": {
+			secret: "otpauth://totp/ViettelIdc:iac?secret=" + secret + "&issuer=ViettelIdc",
+			time:   time.Unix(1111111109, 0),
+			want:   "081804",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := GenerateTOTP(testCase.secret, testCase.time)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != testCase.want {
+				t.Errorf("expected %q, got %q", testCase.want, got)
+			}
+		})
+	}
+}
+
+func TestGenerateTOTP_InvalidSecret(t *testing.T) {
+	for _, secret := range []string{"", "not-base32!", "otpauth://totp/ViettelIdc:iac"} {
+		if _, err := GenerateTOTP(secret, time.Now()); err == nil {
+			t.Errorf("expected error for secret %q", secret)
+		}
+	}
+}
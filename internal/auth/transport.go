@@ -0,0 +1,154 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// ErrRefreshUnavailable is returned when the access token is rejected and
+// there are no credentials to log in again, e.g. with a static access token.
+var ErrRefreshUnavailable = errors.New("access token cannot be refreshed")
+
+// LoginFunc obtains a new access token.
+type LoginFunc func(ctx context.Context) (string, error)
+
+// TokenSource holds the access token shared by every API client of the
+// provider, and renews it when the API rejects it.
+type TokenSource struct {
+	mu    sync.Mutex
+	token string
+	login LoginFunc
+}
+
+// NewTokenSource returns a token source starting with the given token. The
+// login function is used to renew the token, it may be nil when the token
+// cannot be renewed.
+func NewTokenSource(token string, login LoginFunc) *TokenSource {
+	return &TokenSource{
+		token: token,
+		login: login,
+	}
+}
+
+// Token returns the current access token.
+func (s *TokenSource) Token() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.token
+}
+
+// Refresh renews the access token after the API rejected the given token.
+// Concurrent callers rejected with the same token share a single login:
+// callers that arrive after the token has been renewed get the new token.
+func (s *TokenSource) Refresh(ctx context.Context, rejected string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != rejected {
+		return s.token, nil
+	}
+
+	if s.login == nil {
+		return "", ErrRefreshUnavailable
+	}
+
+	token, err := s.login(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token = token
+
+	return token, nil
+}
+
+// Transport is an http.RoundTripper authenticating requests with the token
+// of a TokenSource. When the API answers 401 Unauthorized the token is
+// refreshed once and the request is retried with the new token.
+//
+// Only requests carrying an Authorization header are handled, requests of
+// the login flow itself are passed through untouched.
+type Transport struct {
+	Base   http.RoundTripper
+	Source *TokenSource
+}
+
+// NewTransport returns a Transport wrapping base, http.DefaultTransport if
+// nil.
+func NewTransport(base http.RoundTripper, source *TokenSource) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{
+		Base:   base,
+		Source: source,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") == "" {
+		return t.Base.RoundTrip(req)
+	}
+
+	token := t.Source.Token()
+	res, err := t.Base.RoundTrip(authorize(req, token))
+	if err != nil || res.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+
+	// The request cannot be replayed without a way to rewind its body.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return res, nil
+	}
+
+	// The rejected response is released before logging in again, as the
+	// transports below may hold a request slot until its body is closed. It
+	// is kept in memory, to be returned if the request cannot be retried.
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	ctx := req.Context()
+	newToken, err := t.Source.Refresh(ctx, token)
+	if err != nil {
+		tflog.Debug(ctx, "Unable to refresh Viettelidc API access token", map[string]any{"error": err.Error()})
+		return res, nil
+	}
+
+	tflog.Debug(ctx, "Viettelidc API access token refreshed, retrying request", map[string]any{
+		"method": req.Method,
+		"path":   req.URL.Path,
+	})
+
+	retry := authorize(req, newToken)
+	if req.GetBody != nil {
+		retryBody, err := req.GetBody()
+		if err != nil {
+			return res, nil
+		}
+		retry.Body = retryBody
+	}
+
+	return t.Base.RoundTrip(retry)
+}
+
+// authorize returns a copy of req authenticated with the token, as a
+// RoundTripper must not modify the request it is given.
+func authorize(req *http.Request, token string) *http.Request {
+	clone := req.Clone(req.Context())
+	clone.Header.Set("Authorization", "Bearer "+token)
+	return clone
+}
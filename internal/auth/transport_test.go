@@ -0,0 +1,168 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package auth
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestTransport_RefreshOnUnauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	var logins atomic.Int32
+	source := NewTokenSource("expired", func(ctx context.Context) (string, error) {
+		logins.Add(1)
+		return "fresh", nil
+	})
+	client := &http.Client{Transport: NewTransport(nil, source)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			req.Header.Set("Authorization", "Bearer expired")
+
+			res, err := client.Do(req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer res.Body.Close()
+
+			body, _ := io.ReadAll(res.Body)
+			if res.StatusCode != http.StatusOK || string(body) != "payload" {
+				t.Errorf("expected replayed request to succeed, got %d %q", res.StatusCode, body)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := logins.Load(); got != 1 {
+		t.Errorf("expected concurrent requests to share a single login, got %d", got)
+	}
+	if got := source.Token(); got != "fresh" {
+		t.Errorf("expected refreshed token, got %q", got)
+	}
+}
+
+func TestTransport_NoRefreshAvailable(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport(nil, NewTokenSource("static", nil))}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer static")
+
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", res.StatusCode)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected a single request, got %d", got)
+	}
+}
+
+// closeTracker is a response body recording whether it has been closed.
+type closeTracker struct {
+	io.Reader
+	closed atomic.Bool
+}
+
+func (b *closeTracker) Close() error {
+	b.closed.Store(true)
+	return nil
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestTransport_ReleasesRejectedResponseBeforeLogin(t *testing.T) {
+	rejected := &closeTracker{Reader: strings.NewReader(`{"message": "token expired"}`)}
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusUnauthorized, Body: rejected, Request: req}, nil
+	})
+	source := NewTokenSource("expired", func(ctx context.Context) (string, error) {
+		if !rejected.closed.Load() {
+			t.Error("expected the rejected response to be closed before logging in")
+		}
+		return "", ErrRefreshUnavailable
+	})
+
+	req, err := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer expired")
+
+	res, err := NewTransport(base, source).RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(res.Body)
+	if res.StatusCode != http.StatusUnauthorized || string(body) != `{"message": "token expired"}` {
+		t.Errorf("expected the rejected response to be returned, got %d %q", res.StatusCode, body)
+	}
+}
+
+func TestTransport_SkipsUnauthenticatedRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("expected no Authorization header, got %q", r.Header.Get("Authorization"))
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	source := NewTokenSource("token", func(ctx context.Context) (string, error) {
+		t.Error("unexpected login")
+		return "", nil
+	})
+	client := &http.Client{Transport: NewTransport(nil, source)}
+
+	res, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+}
@@ -0,0 +1,254 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/viettelidc-provider/viettelidc-api-client-go/service/iam"
+	"github.com/viettelidc-provider/viettelidc-api-client-go/service/vpc"
+	"terraform-provider-viettelidc/internal/client/voksapi"
+)
+
+// ProviderData is what resources and data sources get from the provider. It
+// is implemented by *Client; tests can inject a fake, e.g. embedding a Client
+// built with a static InitFunc and overriding the API clients it returns.
+type ProviderData interface {
+	// Region returns the region of the provider, empty when it is not set.
+	Region() string
+	// Regions returns the regions declared in the provider configuration.
+	Regions() []Region
+	// Account returns the account the provider is logged in to.
+	Account(ctx context.Context) (Account, diag.Diagnostics)
+	// Iam returns the IAM API client.
+	Iam(ctx context.Context) (*iam.APIClient, diag.Diagnostics)
+	// Voks and Vpc return the API client of the region, or of the region of
+	// the provider when empty.
+	Voks(ctx context.Context, region string) (*voksapi.Client, diag.Diagnostics)
+	Vpc(ctx context.Context, region string) (*vpc.APIClient, diag.Diagnostics)
+
+	// Lock holds the mutex of key, serializing the operations on the object
+	// it identifies across the resources of the provider.
+	Lock(ctx context.Context, key string) (unlock func(), err error)
+
+	DeferDataSourceRead(request datasource.ReadRequest, response *datasource.ReadResponse) bool
+	DeferResourceRead(request resource.ReadRequest, response *resource.ReadResponse) bool
+	DeferResourcePlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse, attributes ...path.Path) bool
+	DeferImportState(request resource.ImportStateRequest, response *resource.ImportStateResponse) bool
+}
+
+var _ ProviderData = &Client{}
+
+// InitFunc logs in and returns the API configuration of every service.
+type InitFunc func(ctx context.Context) (*Config, diag.Diagnostics)
+
+// Options are the parts of a Client set up when the provider is configured.
+type Options struct {
+	// Region is the region of the provider, empty when it is not set.
+	Region string
+	// Regions are the regions declared in the provider configuration.
+	Regions []Region
+	// HTTPClient is shared by every API client, and so are its
+	// authenticating, retrying, limiting and logging transports.
+	HTTPClient *http.Client
+	// Limiter bounds the requests of every API client.
+	Limiter *Limiter
+}
+
+// Client is the provider data handed to resources and data sources. It holds
+// the API clients of every service, the account information, and the HTTP
+// stack they share, so that a token refreshed by one call is used by all.
+//
+// It is initialized on the first API call rather than when the provider is
+// configured, so that operations which never call the API, such as validate
+// or planning a new resource, neither log in nor need known credentials.
+type Client struct {
+	init    InitFunc
+	options Options
+	unknown []string
+
+	initMu      sync.Mutex
+	initialized bool
+	config      *Config
+	iam         *iam.APIClient
+
+	mu       sync.Mutex
+	regional map[string]*apiClients
+
+	locks MutexKV
+}
+
+// apiClients are the API clients of a region.
+type apiClients struct {
+	voks *voksapi.Client
+	vpc  *vpc.APIClient
+}
+
+// NewClient returns a Client initialized with init on first use.
+func NewClient(options Options, init InitFunc) *Client {
+	return &Client{
+		init:     init,
+		options:  options,
+		regional: make(map[string]*apiClients),
+	}
+}
+
+// NewUnknownClient returns a Client standing for a provider configuration
+// whose attributes are unknown until apply. Every API call reports them.
+func NewUnknownClient(attributes []string) *Client {
+	return &Client{
+		init: func(context.Context) (*Config, diag.Diagnostics) {
+			var diags diag.Diagnostics
+			diags.AddError(
+				"Unknown Viettelidc API Configuration",
+				"The provider cannot create the Viettelidc API client as there are unknown configuration values for "+strings.Join(attributes, ", ")+". "+
+					"Either target apply the source of the values first, set the values statically in the configuration, or use the matching VIETTELIDC_* environment variables.",
+			)
+			return nil, diags
+		},
+		unknown:  attributes,
+		regional: make(map[string]*apiClients),
+	}
+}
+
+// Region returns the region of the provider, empty when it is not set. It
+// does not initialize the Client.
+func (c *Client) Region() string {
+	return c.options.Region
+}
+
+// Regions returns the regions declared in the provider configuration. It
+// does not initialize the Client.
+func (c *Client) Regions() []Region {
+	return c.options.Regions
+}
+
+// HTTPClient returns the HTTP client shared by every API client.
+func (c *Client) HTTPClient() *http.Client {
+	return c.options.HTTPClient
+}
+
+// Limiter returns the limiter shared by every API client.
+func (c *Client) Limiter() *Limiter {
+	return c.options.Limiter
+}
+
+// Lock holds the mutex of key until unlock is called. It does not initialize
+// the Client.
+func (c *Client) Lock(ctx context.Context, key string) (unlock func(), err error) {
+	return c.locks.Lock(ctx, key)
+}
+
+// Unknown reports whether the provider configuration is unknown until apply,
+// in which case resources and data sources should defer their operations
+// when Terraform allows it.
+func (c *Client) Unknown() bool {
+	return c != nil && len(c.unknown) > 0
+}
+
+// Config returns the API configuration of every service, initializing the
+// Client on the first call. Concurrent callers wait for a single
+// initialization. Only a successful one is kept, a failed one, e.g. because
+// of a transient login error, is attempted again by the next call.
+func (c *Client) Config(ctx context.Context) (*Config, diag.Diagnostics) {
+	c.initMu.Lock()
+	defer c.initMu.Unlock()
+
+	if c.initialized {
+		return c.config, nil
+	}
+
+	// The initialization outlives the operation triggering it, which must
+	// not cancel it for the others.
+	config, diags := c.init(context.WithoutCancel(ctx))
+	if diags.HasError() {
+		return nil, diags
+	}
+	c.config = config
+	c.iam = iam.NewAPIClient(config.Iam)
+	c.initialized = true
+	return c.config, diags
+}
+
+// Account returns the account the provider is logged in to, initializing
+// the Client on the first call.
+func (c *Client) Account(ctx context.Context) (Account, diag.Diagnostics) {
+	config, diags := c.Config(ctx)
+	if diags.HasError() {
+		return Account{}, diags
+	}
+	return config.Account, diags
+}
+
+// Iam returns the IAM API client, initializing the Client on the first call.
+func (c *Client) Iam(ctx context.Context) (*iam.APIClient, diag.Diagnostics) {
+	_, diags := c.Config(ctx)
+	if diags.HasError() {
+		return nil, diags
+	}
+	return c.iam, diags
+}
+
+// Voks returns the vOKS API client of the region, or of the region of the
+// provider when empty.
+func (c *Client) Voks(ctx context.Context, region string) (*voksapi.Client, diag.Diagnostics) {
+	clients, diags := c.apiClients(ctx, region)
+	if clients == nil {
+		return nil, diags
+	}
+	return clients.voks, diags
+}
+
+// Vpc returns the VPC API client of the region, or of the region of the
+// provider when empty.
+func (c *Client) Vpc(ctx context.Context, region string) (*vpc.APIClient, diag.Diagnostics) {
+	clients, diags := c.apiClients(ctx, region)
+	if clients == nil {
+		return nil, diags
+	}
+	return clients.vpc, diags
+}
+
+// apiClients returns the API clients of the region, building them on first
+// use.
+func (c *Client) apiClients(ctx context.Context, region string) (*apiClients, diag.Diagnostics) {
+	config, diags := c.Config(ctx)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	if region == "" {
+		region = c.options.Region
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if clients, ok := c.regional[region]; ok {
+		return clients, diags
+	}
+
+	regionConfig, err := config.ForRegion(region)
+	if err != nil {
+		diags.AddError(
+			"Invalid Viettelidc Region",
+			"The provider cannot create the Viettelidc API client of the region: "+err.Error(),
+		)
+		return nil, diags
+	}
+
+	clients := &apiClients{
+		voks: voksapi.NewClient(regionConfig.Voks),
+		vpc:  vpc.NewAPIClient(regionConfig.Vpc),
+	}
+	c.regional[region] = clients
+	return clients, diags
+}
@@ -0,0 +1,134 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/viettelidc-provider/viettelidc-api-client-go/viettelidc"
+)
+
+func TestClient_InitializedOnce(t *testing.T) {
+	var calls atomic.Int32
+	c := NewClient(Options{}, func(ctx context.Context) (*Config, diag.Diagnostics) {
+		calls.Add(1)
+		configuration := &viettelidc.Configuration{BasePath: "https://api.viettelidc.com.vn"}
+		return &Config{Iam: configuration, Voks: configuration, Vpc: configuration}, nil
+	})
+
+	if got := calls.Load(); got != 0 {
+		t.Fatalf("expected no initialization before first use, got %d", got)
+	}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			voksClient, diags := c.Voks(context.Background(), "")
+			if diags.HasError() || voksClient == nil {
+				t.Errorf("unexpected result: %v, %v", voksClient, diags)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected a single initialization, got %d", got)
+	}
+}
+
+func TestClient_InitializationFailure(t *testing.T) {
+	var calls atomic.Int32
+	c := NewClient(Options{}, func(ctx context.Context) (*Config, diag.Diagnostics) {
+		calls.Add(1)
+		var diags diag.Diagnostics
+		diags.AddError("Unable to Create Viettelidc API Client", "login failed")
+		return nil, diags
+	})
+
+	for range 2 {
+		vpcClient, diags := c.Vpc(context.Background(), "")
+		if !diags.HasError() || vpcClient != nil {
+			t.Errorf("expected the initialization error, got %v, %v", vpcClient, diags)
+		}
+	}
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected a failed initialization to be attempted again, got %d", got)
+	}
+}
+
+func TestClient_InitializationRecovers(t *testing.T) {
+	var calls atomic.Int32
+	c := NewClient(Options{}, func(ctx context.Context) (*Config, diag.Diagnostics) {
+		var diags diag.Diagnostics
+		if calls.Add(1) == 1 {
+			diags.AddError("Unable to Create Viettelidc API Client", "login failed: connection reset by peer")
+			return nil, diags
+		}
+		configuration := &viettelidc.Configuration{BasePath: "https://api.viettelidc.com.vn"}
+		return &Config{Iam: configuration, Voks: configuration, Vpc: configuration}, diags
+	})
+
+	if _, diags := c.Voks(context.Background(), ""); !diags.HasError() {
+		t.Fatal("expected the first login to fail")
+	}
+	for range 2 {
+		voksClient, diags := c.Voks(context.Background(), "")
+		if diags.HasError() || voksClient == nil {
+			t.Fatalf("expected the second login to succeed, got %v, %v", voksClient, diags)
+		}
+	}
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected the successful initialization to be kept, got %d", got)
+	}
+}
+
+func TestClient_Bundle(t *testing.T) {
+	httpClient := &http.Client{}
+	limiter := NewLimiter(0, 0)
+	account := Account{Id: "1", DomainId: "domain", CustomerId: "customer"}
+	c := NewClient(Options{Region: "hanoi", HTTPClient: httpClient, Limiter: limiter}, func(ctx context.Context) (*Config, diag.Diagnostics) {
+		configuration := &viettelidc.Configuration{BasePath: "https://api.viettelidc.com.vn", HTTPClient: httpClient}
+		return &Config{Iam: configuration, Voks: configuration, Vpc: configuration, Account: account, Region: "hanoi"}, nil
+	})
+
+	if c.HTTPClient() != httpClient || c.Limiter() != limiter {
+		t.Error("expected the HTTP client and limiter of the options")
+	}
+
+	got, diags := c.Account(context.Background())
+	if diags.HasError() || got != account {
+		t.Errorf("expected account %v, got %v, %v", account, got, diags)
+	}
+
+	iamClient, diags := c.Iam(context.Background())
+	if diags.HasError() || iamClient == nil {
+		t.Errorf("unexpected result: %v, %v", iamClient, diags)
+	}
+}
+
+func TestNewUnknownClient(t *testing.T) {
+	c := NewUnknownClient([]string{"password", "username"})
+	if !c.Unknown() {
+		t.Error("expected the client to be unknown")
+	}
+
+	_, diags := c.Voks(context.Background(), "")
+	if !diags.HasError() || !strings.Contains(diags.Errors()[0].Detail(), "password, username") {
+		t.Errorf("expected an error naming the unknown attributes, got %v", diags)
+	}
+
+	if NewClient(Options{}, nil).Unknown() {
+		t.Error("expected a configured client not to be unknown")
+	}
+}
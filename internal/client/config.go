@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"github.com/viettelidc-provider/viettelidc-api-client-go/viettelidc"
+)
+
+// Account is the account the provider is logged in to.
+type Account struct {
+	Id         string
+	DomainId   string
+	CustomerId string
+}
+
+// Config holds the API configuration of every service, each one with its own
+// base path but sharing the HTTP client and the account information.
+type Config struct {
+	Iam  *viettelidc.Configuration
+	Voks *viettelidc.Configuration
+	Vpc  *viettelidc.Configuration
+
+	Account Account
+
+	// Region is the region of the provider, empty when it is not set.
+	Region string
+	// Regions are the regions declared in the provider configuration.
+	Regions []Region
+}
+
+// ForRegion returns the configuration of the services of another region,
+// served by the API gateway of that region. The endpoint overrides of the
+// provider only apply to its own region.
+func (c *Config) ForRegion(code string) (*Config, error) {
+	if code == "" || code == c.Region {
+		return c, nil
+	}
+
+	region, err := LookupRegion(c.Regions, code)
+	if err != nil {
+		return nil, err
+	}
+
+	regionConfiguration := func(configuration *viettelidc.Configuration) *viettelidc.Configuration {
+		copied := *configuration
+		copied.BasePath = region.Endpoint
+		return &copied
+	}
+
+	return &Config{
+		Iam:     c.Iam,
+		Voks:    regionConfiguration(c.Voks),
+		Vpc:     regionConfiguration(c.Vpc),
+		Account: c.Account,
+		Region:  region.Code,
+		Regions: c.Regions,
+	}, nil
+}
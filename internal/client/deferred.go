@@ -0,0 +1,86 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// DeferDataSourceRead defers the read of a data source when Terraform allows
+// it and either the provider configuration or the data source one is unknown
+// until apply. It reports whether the read has been deferred.
+func (c *Client) DeferDataSourceRead(request datasource.ReadRequest, response *datasource.ReadResponse) bool {
+	if !request.ClientCapabilities.DeferralAllowed {
+		return false
+	}
+
+	switch {
+	case c.Unknown():
+		response.Deferred = &datasource.Deferred{Reason: datasource.DeferredReasonProviderConfigUnknown}
+	case !request.Config.Raw.IsFullyKnown():
+		response.Deferred = &datasource.Deferred{Reason: datasource.DeferredReasonDataSourceConfigUnknown}
+	default:
+		return false
+	}
+	return true
+}
+
+// DeferResourceRead defers the refresh of a resource when Terraform allows it
+// and the provider configuration is unknown until apply. The prior state is
+// kept. It reports whether the read has been deferred.
+func (c *Client) DeferResourceRead(request resource.ReadRequest, response *resource.ReadResponse) bool {
+	if !request.ClientCapabilities.DeferralAllowed || !c.Unknown() {
+		return false
+	}
+
+	response.Deferred = &resource.Deferred{Reason: resource.DeferredReasonProviderConfigUnknown}
+	return true
+}
+
+// DeferImportState defers the import of a resource when Terraform allows it
+// and the provider configuration is unknown until apply. It reports whether
+// the import has been deferred.
+func (c *Client) DeferImportState(request resource.ImportStateRequest, response *resource.ImportStateResponse) bool {
+	if !request.ClientCapabilities.DeferralAllowed || !c.Unknown() {
+		return false
+	}
+
+	response.Deferred = &resource.Deferred{Reason: resource.DeferredReasonProviderConfigUnknown}
+	return true
+}
+
+// DeferResourcePlan defers the change of a resource when Terraform allows it
+// and either the provider configuration or one of the planned attributes,
+// typically the cluster the resource belongs to, is unknown until apply. It
+// reports whether the change has been deferred.
+func (c *Client) DeferResourcePlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse, attributes ...path.Path) bool {
+	if !request.ClientCapabilities.DeferralAllowed {
+		return false
+	}
+
+	if c.Unknown() {
+		response.Deferred = &resource.Deferred{Reason: resource.DeferredReasonProviderConfigUnknown}
+		return true
+	}
+
+	// Nothing is planned on destroy.
+	if request.Plan.Raw.IsNull() {
+		return false
+	}
+
+	for _, attribute := range attributes {
+		var value attr.Value
+		response.Diagnostics.Append(request.Plan.GetAttribute(ctx, attribute, &value)...)
+		if value != nil && value.IsUnknown() {
+			response.Deferred = &resource.Deferred{Reason: resource.DeferredReasonResourceConfigUnknown}
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestClient_DeferResourcePlan(t *testing.T) {
+	testSchema := schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"cluster_id": schema.Int32Attribute{Required: true},
+			"name":       schema.StringAttribute{Required: true},
+		},
+	}
+	objectType := testSchema.Type().TerraformType(context.Background())
+
+	plan := func(clusterId tftypes.Value) tfsdk.Plan {
+		return tfsdk.Plan{
+			Schema: testSchema,
+			Raw: tftypes.NewValue(objectType, map[string]tftypes.Value{
+				"cluster_id": clusterId,
+				"name":       tftypes.NewValue(tftypes.String, "default"),
+			}),
+		}
+	}
+
+	testCases := map[string]struct {
+		client          *Client
+		deferralAllowed bool
+		clusterId       tftypes.Value
+		want            *resource.Deferred
+	}{
+		"known": {
+			client:          NewClient(Options{}, nil),
+			deferralAllowed: true,
+			clusterId:       tftypes.NewValue(tftypes.Number, 42),
+		},
+		"unknown-cluster": {
+			client:          NewClient(Options{}, nil),
+			deferralAllowed: true,
+			clusterId:       tftypes.NewValue(tftypes.Number, tftypes.UnknownValue),
+			want:            &resource.Deferred{Reason: resource.DeferredReasonResourceConfigUnknown},
+		},
+		"unknown-provider": {
+			client:          NewUnknownClient([]string{"password"}),
+			deferralAllowed: true,
+			clusterId:       tftypes.NewValue(tftypes.Number, 42),
+			want:            &resource.Deferred{Reason: resource.DeferredReasonProviderConfigUnknown},
+		},
+		"deferral-not-allowed": {
+			client:    NewUnknownClient([]string{"password"}),
+			clusterId: tftypes.NewValue(tftypes.Number, tftypes.UnknownValue),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			request := resource.ModifyPlanRequest{
+				ClientCapabilities: resource.ModifyPlanClientCapabilities{DeferralAllowed: testCase.deferralAllowed},
+				Plan:               plan(testCase.clusterId),
+			}
+			response := &resource.ModifyPlanResponse{}
+
+			deferred := testCase.client.DeferResourcePlan(context.Background(), request, response, path.Root("cluster_id"))
+			if response.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", response.Diagnostics)
+			}
+			if deferred != (testCase.want != nil) {
+				t.Errorf("expected deferred to be %t", testCase.want != nil)
+			}
+			if testCase.want == nil && response.Deferred != nil || testCase.want != nil && (response.Deferred == nil || *response.Deferred != *testCase.want) {
+				t.Errorf("expected %v, got %v", testCase.want, response.Deferred)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// FieldPaths maps the request field names of an API to the attributes they
+// are configured by. Field names are matched ignoring case, underscores and
+// dashes, so "minNode" matches a "min_node" key.
+type FieldPaths map[string]path.Path
+
+// lookup returns the attribute path of an API field. Nested fields such as
+// "scalingConfig.minNode" are also looked up by their last segment.
+func (f FieldPaths) lookup(field string) (path.Path, bool) {
+	candidates := []string{field}
+	if i := strings.LastIndexAny(field, ".]"); i >= 0 && i < len(field)-1 {
+		candidates = append(candidates, field[i+1:])
+	}
+	for _, candidate := range candidates {
+		candidate = normalizeName(candidate)
+		for name, attributePath := range f {
+			if normalizeName(name) == candidate {
+				return attributePath, true
+			}
+		}
+	}
+	return path.Empty(), false
+}
+
+// ErrorDiagnostics returns the diagnostics of err, as returned together with
+// res by an API call. The error is reported as "<detail>, unexpected error:
+// <message>", followed by the error code and the request id to quote to the
+// support. Rejected fields found in fields are reported on their attribute.
+func ErrorDiagnostics(summary, detail string, res *http.Response, err error, fields FieldPaths) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		apiErr = NewAPIError(res, err)
+	}
+
+	var reference strings.Builder
+	if apiErr.Code != "" {
+		reference.WriteString("\nError code: " + apiErr.Code)
+	}
+	if apiErr.RequestID != "" {
+		reference.WriteString("\nRequest ID: " + apiErr.RequestID)
+	}
+
+	var unmatched strings.Builder
+	for _, fieldErr := range apiErr.Fields {
+		if attributePath, ok := fields.lookup(fieldErr.Field); ok {
+			diags.AddAttributeError(attributePath, summary,
+				"The API rejected this value: "+fieldErr.Message+withNewline(reference.String()))
+			continue
+		}
+		unmatched.WriteString("\n  - " + fieldErr.Field + ": " + fieldErr.Message)
+	}
+
+	message := detail + ", unexpected error: " + apiErr.Error()
+	if unmatched.Len() > 0 {
+		message += "\n\nRejected fields:" + unmatched.String()
+	}
+	message += withNewline(reference.String())
+	diags.AddError(summary, message)
+
+	return diags
+}
+
+// withNewline separates a non-empty reference block from the text above it.
+func withNewline(reference string) string {
+	if reference == "" {
+		return ""
+	}
+	return "\n" + reference
+}
@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+func TestErrorDiagnostics(t *testing.T) {
+	fields := FieldPaths{
+		"name":    path.Root("name"),
+		"maxNode": path.Root("scaling_config").AtName("max_node"),
+	}
+	res := &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Header:     http.Header{"X-Request-Id": []string{"req-1"}},
+	}
+	err := swaggerError{
+		status: "400 Bad Request",
+		body: []byte(`{"code": "INVALID", "message": "Validation failed", "errors": [` +
+			`{"field": "scalingConfig.max_node", "message": "must be at most 10"},` +
+			`{"field": "taints[0].effect", "message": "is not supported"}]}`),
+	}
+
+	diags := ErrorDiagnostics("Error creating Node Group", "Could not create Node Group", res, err, fields)
+
+	if len(diags) != 2 {
+		t.Fatalf("expected 2 diagnostics, got: %v", diags)
+	}
+
+	attributeDiag, ok := diags[0].(diag.DiagnosticWithPath)
+	if !ok {
+		t.Fatalf("expected an attribute diagnostic, got: %v", diags[0])
+	}
+	if want := path.Root("scaling_config").AtName("max_node"); !attributeDiag.Path().Equal(want) {
+		t.Errorf("expected path %s, got: %s", want, attributeDiag.Path())
+	}
+	if !strings.Contains(attributeDiag.Detail(), "must be at most 10") || !strings.Contains(attributeDiag.Detail(), "Request ID: req-1") {
+		t.Errorf("unexpected attribute detail: %s", attributeDiag.Detail())
+	}
+
+	detail := diags[1].Detail()
+	for _, want := range []string{
+		"Could not create Node Group, unexpected error: 400 Bad Request: Validation failed",
+		"taints[0].effect: is not supported",
+		"Error code: INVALID",
+		"Request ID: req-1",
+	} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("expected detail to contain %q, got: %s", want, detail)
+		}
+	}
+	if strings.Contains(detail, "must be at most 10") {
+		t.Errorf("expected the attribute error to be left out of the detail, got: %s", detail)
+	}
+}
+
+func TestErrorDiagnosticsWrapped(t *testing.T) {
+	res := &http.Response{
+		StatusCode: http.StatusInternalServerError,
+		Header:     http.Header{"X-Trace-Id": []string{"trace-1"}},
+	}
+	err := NewAPIError(res, swaggerError{status: "500 Internal Server Error"})
+
+	diags := ErrorDiagnostics("Error waiting", "Could not wait", nil, errors.Join(errors.New("refresh"), err), nil)
+
+	if len(diags) != 1 || !strings.Contains(diags[0].Detail(), "Request ID: trace-1") {
+		t.Errorf("expected the request id of the wrapped error, got: %v", diags)
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrorKind classifies a failed API call.
+type ErrorKind int
+
+const (
+	// ErrorKindUnknown is any failure not covered by another kind, such as a
+	// connection error.
+	ErrorKindUnknown ErrorKind = iota
+	// ErrorKindNotFound means the object does not exist (anymore).
+	ErrorKindNotFound
+	// ErrorKindPermission means the credentials are invalid or lack access.
+	ErrorKindPermission
+	// ErrorKindValidation means the API rejected the request parameters.
+	ErrorKindValidation
+	// ErrorKindConflict means the object is in a state that does not allow
+	// the operation, e.g. because another operation is in progress.
+	ErrorKindConflict
+	// ErrorKindServer means the API failed to handle a valid request.
+	ErrorKindServer
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorKindNotFound:
+		return "not found"
+	case ErrorKindPermission:
+		return "permission denied"
+	case ErrorKindValidation:
+		return "validation failed"
+	case ErrorKindConflict:
+		return "conflict"
+	case ErrorKindServer:
+		return "server error"
+	default:
+		return "unknown"
+	}
+}
+
+// The swagger clients report failed calls with the response status, e.g.
+// "404 Not Found", as error message.
+var statusErrorRegexp = regexp.MustCompile(`^([1-5][0-9]{2})\b`)
+
+// ClassifyError returns the kind of err, as returned together with res by an
+// API call. It returns ErrorKindUnknown when err is nil.
+func ClassifyError(res *http.Response, err error) ErrorKind {
+	if err == nil {
+		return ErrorKindUnknown
+	}
+
+	statusCode := 0
+	if res != nil {
+		statusCode = res.StatusCode
+	} else if match := statusErrorRegexp.FindStringSubmatch(err.Error()); match != nil {
+		statusCode, _ = strconv.Atoi(match[1])
+	}
+
+	switch {
+	case statusCode == http.StatusNotFound, statusCode == http.StatusGone:
+		return ErrorKindNotFound
+	case statusCode == http.StatusUnauthorized, statusCode == http.StatusForbidden:
+		return ErrorKindPermission
+	case statusCode == http.StatusBadRequest, statusCode == http.StatusUnprocessableEntity:
+		return ErrorKindValidation
+	case statusCode == http.StatusConflict, statusCode == http.StatusPreconditionFailed, statusCode == http.StatusLocked:
+		return ErrorKindConflict
+	case statusCode >= http.StatusInternalServerError:
+		return ErrorKindServer
+	default:
+		return ErrorKindUnknown
+	}
+}
+
+// IsNotFound reports whether err, as returned together with res by an API
+// call, means the object does not exist.
+func IsNotFound(res *http.Response, err error) bool {
+	return ClassifyError(res, err) == ErrorKindNotFound
+}
+
+// FieldError is a rejected request field reported by the API.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// APIError is a failed API call, with the error response body decoded.
+type APIError struct {
+	Kind       ErrorKind
+	StatusCode int
+	Code       string
+	Message    string
+	Fields     []FieldError
+	RequestID  string
+
+	Err error
+}
+
+// NewAPIError decodes err, as returned together with res by an API call. The
+// swagger clients keep the response body of a failed call in the error.
+func NewAPIError(res *http.Response, err error) *APIError {
+	apiErr := &APIError{
+		Kind: ClassifyError(res, err),
+		Err:  err,
+	}
+	if res != nil {
+		apiErr.StatusCode = res.StatusCode
+		apiErr.RequestID = requestIDFromHeader(res.Header)
+	} else if match := statusErrorRegexp.FindStringSubmatch(err.Error()); match != nil {
+		apiErr.StatusCode, _ = strconv.Atoi(match[1])
+	}
+
+	var bodyErr interface{ Body() []byte }
+	if errors.As(err, &bodyErr) {
+		apiErr.decodeBody(bodyErr.Body())
+	}
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	message := e.Err.Error()
+	if e.Message != "" && !strings.Contains(message, e.Message) {
+		message += ": " + e.Message
+	}
+	return message
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// decodeBody reads the error code, message, field errors and request id from
+// a JSON error body. The services do not agree on a format, so the common
+// spellings of each key are accepted, at the top level or nested in "error".
+func (e *APIError) decodeBody(body []byte) {
+	var object map[string]json.RawMessage
+	if json.Unmarshal(body, &object) != nil {
+		return
+	}
+	if nested, ok := lookupKey(object, "error"); ok {
+		var nestedObject map[string]json.RawMessage
+		if json.Unmarshal(nested, &nestedObject) == nil {
+			maps.Copy(object, nestedObject)
+		}
+	}
+
+	e.Code = lookupString(object, "code", "errorCode")
+	e.Message = lookupString(object, "message", "msg", "errorMessage", "errorDescription", "detail", "error")
+	if requestID := lookupString(object, "requestId", "traceId", "correlationId"); requestID != "" {
+		e.RequestID = requestID
+	}
+
+	for _, key := range []string{"errors", "fieldErrors", "violations", "details"} {
+		raw, ok := lookupKey(object, key)
+		if !ok {
+			continue
+		}
+
+		var list []map[string]json.RawMessage
+		if json.Unmarshal(raw, &list) == nil {
+			for _, item := range list {
+				field := lookupString(item, "field", "name", "param", "property", "path")
+				if field == "" {
+					continue
+				}
+				e.Fields = append(e.Fields, FieldError{
+					Field:   field,
+					Message: lookupString(item, "message", "msg", "reason", "defaultMessage", "description"),
+				})
+			}
+			continue
+		}
+
+		var byField map[string]json.RawMessage
+		if json.Unmarshal(raw, &byField) == nil {
+			for _, field := range slices.Sorted(maps.Keys(byField)) {
+				message := rawString(byField[field])
+				var messages []string
+				if message == "" && json.Unmarshal(byField[field], &messages) == nil {
+					message = strings.Join(messages, "; ")
+				}
+				e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+			}
+		}
+	}
+}
+
+// lookupKey returns the value of the first key of object matching key,
+// ignoring case, underscores and dashes.
+func lookupKey(object map[string]json.RawMessage, key string) (json.RawMessage, bool) {
+	key = normalizeName(key)
+	for k, v := range object {
+		if normalizeName(k) == key {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// lookupString returns the first of keys found in object as a string.
+func lookupString(object map[string]json.RawMessage, keys ...string) string {
+	for _, key := range keys {
+		if raw, ok := lookupKey(object, key); ok {
+			if value := rawString(raw); value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// rawString returns a JSON string or number as a string.
+func rawString(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var n json.Number
+	if json.Unmarshal(raw, &n) == nil {
+		return n.String()
+	}
+	return ""
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+}
@@ -0,0 +1,169 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	testCases := map[string]struct {
+		statusCode int
+		err        error
+		want       ErrorKind
+	}{
+		"no-error": {
+			statusCode: http.StatusOK,
+			want:       ErrorKindUnknown,
+		},
+		"not-found": {
+			statusCode: http.StatusNotFound,
+			err:        errors.New("404 Not Found"),
+			want:       ErrorKindNotFound,
+		},
+		"gone": {
+			statusCode: http.StatusGone,
+			err:        errors.New("410 Gone"),
+			want:       ErrorKindNotFound,
+		},
+		"unauthorized": {
+			statusCode: http.StatusUnauthorized,
+			err:        errors.New("401 Unauthorized"),
+			want:       ErrorKindPermission,
+		},
+		"forbidden": {
+			statusCode: http.StatusForbidden,
+			err:        errors.New("403 Forbidden"),
+			want:       ErrorKindPermission,
+		},
+		"bad-request": {
+			statusCode: http.StatusBadRequest,
+			err:        errors.New("400 Bad Request"),
+			want:       ErrorKindValidation,
+		},
+		"conflict": {
+			statusCode: http.StatusConflict,
+			err:        errors.New("409 Conflict"),
+			want:       ErrorKindConflict,
+		},
+		"server": {
+			statusCode: http.StatusBadGateway,
+			err:        errors.New("502 Bad Gateway"),
+			want:       ErrorKindServer,
+		},
+		"decode-error": {
+			statusCode: http.StatusOK,
+			err:        errors.New("invalid character '<' looking for beginning of value"),
+			want:       ErrorKindUnknown,
+		},
+		"status-in-message": {
+			err:  errors.New("404 Not Found"),
+			want: ErrorKindNotFound,
+		},
+		"connection-error": {
+			err:  errors.New("dial tcp: connection refused"),
+			want: ErrorKindUnknown,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var res *http.Response
+			if testCase.statusCode != 0 {
+				res = &http.Response{StatusCode: testCase.statusCode}
+			}
+
+			if got := ClassifyError(res, testCase.err); got != testCase.want {
+				t.Errorf("expected %s, got: %s", testCase.want, got)
+			}
+		})
+	}
+}
+
+// swaggerError mimics the GenericSwaggerError of the generated clients.
+type swaggerError struct {
+	status string
+	body   []byte
+}
+
+func (e swaggerError) Error() string { return e.status }
+func (e swaggerError) Body() []byte  { return e.body }
+
+func TestNewAPIError(t *testing.T) {
+	testCases := map[string]struct {
+		body          string
+		header        http.Header
+		wantCode      string
+		wantMessage   string
+		wantRequestID string
+		wantFields    []FieldError
+	}{
+		"flat": {
+			body:          `{"code": "NG_QUOTA", "message": "Node quota exceeded", "requestId": "req-1"}`,
+			wantCode:      "NG_QUOTA",
+			wantMessage:   "Node quota exceeded",
+			wantRequestID: "req-1",
+		},
+		"nested": {
+			body:          `{"error": {"error_code": 4001, "msg": "Invalid node group", "trace_id": "trace-1"}}`,
+			wantCode:      "4001",
+			wantMessage:   "Invalid node group",
+			wantRequestID: "trace-1",
+		},
+		"field-list": {
+			body:        `{"message": "Validation failed", "errors": [{"field": "maxNode", "message": "must be at most 10"}, {"message": "ignored"}]}`,
+			wantMessage: "Validation failed",
+			wantFields:  []FieldError{{Field: "maxNode", Message: "must be at most 10"}},
+		},
+		"field-map": {
+			body:        `{"message": "Validation failed", "fieldErrors": {"name": ["is required", "is too short"], "minNode": "must be positive"}}`,
+			wantMessage: "Validation failed",
+			wantFields: []FieldError{
+				{Field: "minNode", Message: "must be positive"},
+				{Field: "name", Message: "is required; is too short"},
+			},
+		},
+		"header-request-id": {
+			body:          `{"message": "Cluster not found"}`,
+			header:        http.Header{"X-Request-Id": []string{"req-2"}},
+			wantMessage:   "Cluster not found",
+			wantRequestID: "req-2",
+		},
+		"not-json": {
+			body: `<html>Bad Gateway</html>`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			res := &http.Response{StatusCode: http.StatusBadRequest, Header: testCase.header}
+			err := swaggerError{status: "400 Bad Request", body: []byte(testCase.body)}
+
+			apiErr := NewAPIError(res, err)
+
+			if apiErr.Kind != ErrorKindValidation {
+				t.Errorf("expected kind %s, got: %s", ErrorKindValidation, apiErr.Kind)
+			}
+			if apiErr.Code != testCase.wantCode {
+				t.Errorf("expected code %q, got: %q", testCase.wantCode, apiErr.Code)
+			}
+			if apiErr.Message != testCase.wantMessage {
+				t.Errorf("expected message %q, got: %q", testCase.wantMessage, apiErr.Message)
+			}
+			if apiErr.RequestID != testCase.wantRequestID {
+				t.Errorf("expected request id %q, got: %q", testCase.wantRequestID, apiErr.RequestID)
+			}
+			if len(apiErr.Fields) != len(testCase.wantFields) {
+				t.Fatalf("expected fields %v, got: %v", testCase.wantFields, apiErr.Fields)
+			}
+			for i, field := range testCase.wantFields {
+				if apiErr.Fields[i] != field {
+					t.Errorf("expected field %v, got: %v", field, apiErr.Fields[i])
+				}
+			}
+		})
+	}
+}
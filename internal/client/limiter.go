@@ -0,0 +1,144 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxRequestsPerSecond is the request rate unless configured
+	// otherwise.
+	DefaultMaxRequestsPerSecond = 10
+	// DefaultMaxConcurrentRequests is the number of requests in flight
+	// unless configured otherwise.
+	DefaultMaxConcurrentRequests = 5
+)
+
+// Limiter bounds the rate and the concurrency of the API requests. A single
+// Limiter is shared by every API client of the provider.
+type Limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+
+	slots chan struct{}
+}
+
+// NewLimiter returns a Limiter letting through at most requestsPerSecond
+// requests per second and maxConcurrent requests at a time. A value of zero
+// or less lifts the matching limit.
+func NewLimiter(requestsPerSecond float64, maxConcurrent int) *Limiter {
+	l := &Limiter{}
+	if requestsPerSecond > 0 {
+		l.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+	if maxConcurrent > 0 {
+		l.slots = make(chan struct{}, maxConcurrent)
+	}
+	return l
+}
+
+// Acquire waits until a request may be sent. Every successful call must be
+// followed by a call to Release once the request is done.
+func (l *Limiter) Acquire(ctx context.Context) error {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if wait := l.reserve(); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			l.Release()
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// Release frees the slot taken by Acquire.
+func (l *Limiter) Release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// reserve books the next point in time a request may be sent at, and returns
+// how long to wait until then.
+func (l *Limiter) reserve() time.Duration {
+	if l.interval == 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	return at.Sub(now)
+}
+
+// LimitTransport sends the requests of Base through Limiter. A request holds
+// its slot until its response body is closed.
+type LimitTransport struct {
+	Base    http.RoundTripper
+	Limiter *Limiter
+}
+
+// NewLimitTransport returns a LimitTransport sending the requests of base
+// through limiter.
+func NewLimitTransport(base http.RoundTripper, limiter *Limiter) *LimitTransport {
+	return &LimitTransport{
+		Base:    base,
+		Limiter: limiter,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *LimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.Limiter.Acquire(req.Context()); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+
+	res, err := t.Base.RoundTrip(req)
+	if err != nil {
+		t.Limiter.Release()
+		return nil, err
+	}
+
+	res.Body = &releaseOnClose{ReadCloser: res.Body, release: t.Limiter.Release}
+	return res, nil
+}
+
+// releaseOnClose releases a limiter slot the first time it is closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -0,0 +1,200 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// LogSubsystem is the tflog subsystem the API requests are logged in. Its
+// level is set with the TF_LOG_PROVIDER_VIETTELIDC_HTTP environment variable
+// and defaults to the provider one.
+const LogSubsystem = "viettelidc_http"
+
+const (
+	redacted = "***"
+
+	// maxLoggedBody is the number of bytes of a body logged at most.
+	maxLoggedBody = 16 * 1024
+)
+
+var (
+	// requestIDHeaders are the response headers the gateway reports the id
+	// of a request in, by order of preference.
+	requestIDHeaders = []string{"X-Request-Id", "X-Trace-Id", "X-Correlation-Id"}
+
+	// secretPatterns mask bearer tokens and JWTs wherever they end up in a
+	// log field, as a last line of defense.
+	secretPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)bearer\s+[\w\-.~+/]+=*`),
+		regexp.MustCompile(`eyJ[\w-]+\.[\w-]+\.[\w-]*`),
+	}
+)
+
+// LoggingTransport logs every request sent through Base, and its response,
+// in the LogSubsystem tflog subsystem. Methods, paths, statuses, latencies
+// and request ids are logged at the debug level, bodies at the trace level
+// with passwords, MFA codes, tokens and kubeconfigs redacted.
+type LoggingTransport struct {
+	Base http.RoundTripper
+}
+
+// NewLoggingTransport returns a LoggingTransport logging the requests of base.
+func NewLoggingTransport(base http.RoundTripper) *LoggingTransport {
+	return &LoggingTransport{
+		Base: base,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := tflog.NewSubsystem(req.Context(), LogSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_VIETTELIDC_HTTP"))
+	ctx = tflog.SubsystemMaskAllFieldValuesRegexes(ctx, LogSubsystem, secretPatterns...)
+
+	fields := map[string]any{
+		"http_method": req.Method,
+		"http_path":   req.URL.Path,
+	}
+
+	tflog.SubsystemDebug(ctx, LogSubsystem, "Sending API request", fields)
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			body.Close()
+			tflog.SubsystemTrace(ctx, LogSubsystem, "API request body", map[string]any{
+				"http_path": req.URL.Path,
+				"http_body": RedactBody(req.URL.Path, data),
+			})
+		}
+	}
+
+	start := time.Now()
+	res, err := t.Base.RoundTrip(req)
+	fields["latency_ms"] = time.Since(start).Milliseconds()
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(ctx, LogSubsystem, "API request failed", fields)
+		return nil, err
+	}
+
+	fields["http_status"] = res.StatusCode
+	if id := requestIDFromHeader(res.Header); id != "" {
+		fields["request_id"] = id
+	}
+	tflog.SubsystemDebug(ctx, LogSubsystem, "Received API response", fields)
+
+	// The body is buffered to be logged, responses of the API are small
+	// JSON documents.
+	data, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(data))
+
+	if len(data) > 0 {
+		tflog.SubsystemTrace(ctx, LogSubsystem, "API response body", map[string]any{
+			"http_path": req.URL.Path,
+			"http_body": RedactBody(req.URL.Path, data),
+		})
+	}
+
+	return res, nil
+}
+
+// RedactBody returns the body of a request to or a response from path, with
+// the values of secret JSON fields replaced. Kubeconfigs are redacted as a
+// whole.
+func RedactBody(path string, body []byte) string {
+	if strings.Contains(strings.ToLower(path), "kubeconfig") || looksLikeKubeconfig(body) {
+		return fmt.Sprintf("%s (kubeconfig, %d bytes)", redacted, len(body))
+	}
+
+	body = redactJSON(body)
+	if len(body) > maxLoggedBody {
+		return fmt.Sprintf("%s... (%d bytes truncated)", body[:maxLoggedBody], len(body)-maxLoggedBody)
+	}
+	return string(body)
+}
+
+// redactJSON returns the JSON document body with the values of secret fields
+// replaced, or body unchanged when it is not JSON.
+func redactJSON(body []byte) []byte {
+	var document any
+	if err := json.Unmarshal(body, &document); err != nil {
+		return body
+	}
+	redactedBody, err := json.Marshal(redactValue(document))
+	if err != nil {
+		return body
+	}
+	return redactedBody
+}
+
+// redactValue replaces the values of the secret fields of a decoded JSON
+// document.
+func redactValue(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, field := range value {
+			if isSecretField(key) {
+				value[key] = redacted
+				continue
+			}
+			value[key] = redactValue(field)
+		}
+	case []any:
+		for i, item := range value {
+			value[i] = redactValue(item)
+		}
+	case string:
+		if looksLikeKubeconfig([]byte(value)) {
+			return redacted
+		}
+	}
+	return value
+}
+
+// isSecretField reports whether a JSON field name holds a secret, whatever
+// its casing or word separators.
+func isSecretField(name string) bool {
+	name = strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+	switch name {
+	case "mfacode", "otp", "otpcode", "totp", "passcode", "authorization":
+		return true
+	}
+	for _, secret := range []string{"password", "token", "secret", "kubeconfig", "privatekey", "clientkey"} {
+		if strings.Contains(name, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// looksLikeKubeconfig reports whether data is a YAML kubeconfig.
+func looksLikeKubeconfig(data []byte) bool {
+	return bytes.Contains(data, []byte("kind: Config")) ||
+		bytes.Contains(data, []byte("client-key-data")) ||
+		bytes.Contains(data, []byte("certificate-authority-data"))
+}
+
+// requestIDFromHeader returns the id of the request the gateway reported in
+// the response header, if any.
+func requestIDFromHeader(header http.Header) string {
+	for _, name := range requestIDHeaders {
+		if id := header.Get(name); id != "" {
+			return id
+		}
+	}
+	return ""
+}
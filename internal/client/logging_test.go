@@ -0,0 +1,130 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestRedactBody(t *testing.T) {
+	testCases := map[string]struct {
+		path       string
+		body       string
+		want       []string
+		wantAbsent []string
+	}{
+		"login": {
+			path:       "/iam/v1/auth/login",
+			body:       `{"domainId":"d-1","username":"iac","password":"Vtdc@12345","mfa_code":"123456"}`,
+			want:       []string{`"username":"iac"`, `"password":"***"`, `"mfa_code":"***"`},
+			wantAbsent: []string{"Vtdc@12345", "123456"},
+		},
+		"token-response": {
+			path:       "/iam/v1/auth/login",
+			body:       `{"data":{"accessToken":"abc.def.ghi","expiresIn":3600}}`,
+			want:       []string{`"accessToken":"***"`, `"expiresIn":3600`},
+			wantAbsent: []string{"abc.def.ghi"},
+		},
+		"nested-list": {
+			path:       "/voks/v1/clusters",
+			body:       `{"data":[{"name":"c-1","kubeconfig":"apiVersion: v1"}]}`,
+			want:       []string{`"name":"c-1"`, `"kubeconfig":"***"`},
+			wantAbsent: []string{"apiVersion"},
+		},
+		"kubeconfig-path": {
+			path:       "/voks/v1/clusters/c-1/kubeconfig",
+			body:       `{"data":"anything"}`,
+			want:       []string{"*** (kubeconfig"},
+			wantAbsent: []string{"anything"},
+		},
+		"kubeconfig-yaml": {
+			path:       "/voks/v1/clusters/c-1/download",
+			body:       "apiVersion: v1\nkind: Config\nusers:\n- user:\n    client-key-data: c2VjcmV0\n",
+			want:       []string{"*** (kubeconfig"},
+			wantAbsent: []string{"c2VjcmV0"},
+		},
+		"plain-text": {
+			path: "/vpc/v1/vpcs",
+			body: "Bad Gateway",
+			want: []string{"Bad Gateway"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got := RedactBody(testCase.path, []byte(testCase.body))
+			for _, want := range testCase.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("expected %q in %q", want, got)
+				}
+			}
+			for _, absent := range testCase.wantAbsent {
+				if strings.Contains(got, absent) {
+					t.Errorf("unexpected %q in %q", absent, got)
+				}
+			}
+		})
+	}
+}
+
+func TestLoggingTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-42")
+		_, _ = w.Write([]byte(`{"data":{"accessToken":"server-token"}}`))
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	t.Setenv("TF_LOG_PROVIDER_VIETTELIDC_HTTP", "TRACE")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/iam/v1/auth/login", strings.NewReader(`{"password":"Vtdc@12345"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer static-token")
+
+	res, err := (&http.Client{Transport: NewLoggingTransport(http.DefaultTransport)}).Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+
+	if string(body) != `{"data":{"accessToken":"server-token"}}` {
+		t.Errorf("expected the response body to be preserved, got %q", body)
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sawResponse bool
+	for _, entry := range entries {
+		if entry["@message"] == "Received API response" {
+			sawResponse = true
+			if entry["request_id"] != "req-42" || entry["http_status"] != float64(http.StatusOK) || entry["http_path"] != "/iam/v1/auth/login" {
+				t.Errorf("unexpected response entry: %v", entry)
+			}
+		}
+	}
+	if !sawResponse {
+		t.Errorf("expected a response entry, got %v", entries)
+	}
+
+	for _, secret := range []string{"Vtdc@12345", "server-token", "static-token"} {
+		if strings.Contains(output.String(), secret) {
+			t.Errorf("unexpected %q in the logs:\n%s", secret, output.String())
+		}
+	}
+}
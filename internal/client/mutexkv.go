@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"sync"
+)
+
+// MutexKV is a set of mutexes identified by a key, such as the id of the
+// cluster an operation mutates. The zero value is ready to use.
+type MutexKV struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+// keyLock is the mutex of a key, dropped once no caller holds or waits for it.
+type keyLock struct {
+	held    chan struct{}
+	waiters int
+}
+
+// Lock waits until the mutex of key is free, then holds it until unlock is
+// called. It gives up when ctx is done.
+func (m *MutexKV) Lock(ctx context.Context, key string) (unlock func(), err error) {
+	m.mu.Lock()
+	if m.locks == nil {
+		m.locks = make(map[string]*keyLock)
+	}
+	lock, ok := m.locks[key]
+	if !ok {
+		lock = &keyLock{held: make(chan struct{}, 1)}
+		m.locks[key] = lock
+	}
+	lock.waiters++
+	m.mu.Unlock()
+
+	select {
+	case lock.held <- struct{}{}:
+	case <-ctx.Done():
+		m.release(key, lock, false)
+		return nil, ctx.Err()
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.release(key, lock, true)
+		})
+	}, nil
+}
+
+func (m *MutexKV) release(key string, lock *keyLock, held bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if held {
+		<-lock.held
+	}
+	lock.waiters--
+	if lock.waiters == 0 {
+		delete(m.locks, key)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMutexKV_SerializesKey(t *testing.T) {
+	var m MutexKV
+	var holders, maxHolders atomic.Int32
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			unlock, err := m.Lock(context.Background(), "cluster/1")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer unlock()
+
+			n := holders.Add(1)
+			for {
+				current := maxHolders.Load()
+				if n <= current || maxHolders.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			holders.Add(-1)
+		}()
+	}
+	wg.Wait()
+
+	if got := maxHolders.Load(); got != 1 {
+		t.Errorf("expected a single holder at a time, got %d", got)
+	}
+	if len(m.locks) != 0 {
+		t.Errorf("expected released locks to be dropped, got %d", len(m.locks))
+	}
+}
+
+func TestMutexKV_IndependentKeys(t *testing.T) {
+	var m MutexKV
+
+	unlock, err := m.Lock(context.Background(), "cluster/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	unlockOther, err := m.Lock(ctx, "cluster/2")
+	if err != nil {
+		t.Fatalf("expected another key to be free, got: %s", err)
+	}
+	unlockOther()
+}
+
+func TestMutexKV_Cancel(t *testing.T) {
+	var m MutexKV
+
+	unlock, err := m.Lock(context.Background(), "cluster/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := m.Lock(ctx, "cluster/1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the wait to give up, got: %v", err)
+	}
+
+	unlock()
+	unlock()
+
+	relock, err := m.Lock(context.Background(), "cluster/1")
+	if err != nil {
+		t.Fatalf("expected the key to be free after unlock, got: %s", err)
+	}
+	relock()
+}
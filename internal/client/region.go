@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Region is a ViettelIdc site, served by an API gateway of its own.
+//
+// The ViettelIdc API does not list its regions, their gateways or their data
+// centers, so regions are declared in the provider configuration from the
+// values shown in the ViettelIdc portal.
+type Region struct {
+	// Code identifies the region in the configuration.
+	Code string
+	Name string
+	// Endpoint is the base URL of the API gateway of the region.
+	Endpoint string
+	// HostId identifies the region in the VPC API, nil when it is not set.
+	HostId *int32
+	Zones  []AvailabilityZone
+}
+
+// AvailabilityZone is a data center of a region.
+type AvailabilityZone struct {
+	Code string
+	Name string
+}
+
+// LookupRegion returns the region identified by code among regions.
+func LookupRegion(regions []Region, code string) (Region, error) {
+	codes := make([]string, 0, len(regions))
+	for _, region := range regions {
+		if region.Code == code {
+			return region, nil
+		}
+		codes = append(codes, region.Code)
+	}
+	if len(codes) == 0 {
+		return Region{}, fmt.Errorf("unknown region %q, no region is declared in the regions argument of the provider", code)
+	}
+	sort.Strings(codes)
+	return Region{}, fmt.Errorf("unknown region %q, expected one of %s", code, strings.Join(codes, ", "))
+}
@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/viettelidc-provider/viettelidc-api-client-go/viettelidc"
+)
+
+func TestConfig_ForRegion(t *testing.T) {
+	iam := &viettelidc.Configuration{BasePath: "https://api-hanoi.viettelidc.com.vn", AccessToken: "token"}
+	voks := &viettelidc.Configuration{BasePath: "http://localhost:8080", AccessToken: "token"}
+	regions := []Region{
+		{Code: "hanoi", Endpoint: "https://api-hanoi.viettelidc.com.vn"},
+		{Code: "hcmc", Endpoint: "https://api-hcmc.viettelidc.com.vn"},
+	}
+	config := &Config{Iam: iam, Voks: voks, Vpc: iam, Region: "hanoi", Regions: regions}
+
+	for _, code := range []string{"", "hanoi"} {
+		got, err := config.ForRegion(code)
+		if err != nil || got != config {
+			t.Errorf("%q: expected the provider configuration, got %v, %v", code, got, err)
+		}
+	}
+
+	got, err := config.ForRegion("hcmc")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got.Voks.BasePath != "https://api-hcmc.viettelidc.com.vn" || got.Vpc.BasePath != "https://api-hcmc.viettelidc.com.vn" {
+		t.Errorf("expected the HCMC gateway, got %s and %s", got.Voks.BasePath, got.Vpc.BasePath)
+	}
+	if got.Iam != iam {
+		t.Errorf("expected the IAM configuration of the provider, got %s", got.Iam.BasePath)
+	}
+	if got.Voks.AccessToken != "token" || got.Region != "hcmc" {
+		t.Errorf("expected the account of the provider in region hcmc, got %+v", got)
+	}
+	if voks.BasePath != "http://localhost:8080" {
+		t.Errorf("expected the provider configuration to be left untouched, got %s", voks.BasePath)
+	}
+
+	if _, err := config.ForRegion("saigon"); err == nil {
+		t.Error("expected an unknown region error")
+	}
+
+	config.Regions = nil
+	if _, err := config.ForRegion("hcmc"); err == nil || !strings.Contains(err.Error(), "no region is declared") {
+		t.Errorf("expected an undeclared region error, got %v", err)
+	}
+}
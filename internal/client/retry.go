@@ -0,0 +1,161 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is the number of times a request is retried unless
+	// configured otherwise.
+	DefaultMaxRetries = 4
+	// DefaultRetryMaxWait is the longest wait between two attempts unless
+	// configured otherwise.
+	DefaultRetryMaxWait = 30 * time.Second
+
+	defaultRetryMinWait = time.Second
+)
+
+type retrySafeKey struct{}
+
+// WithRetrySafe marks the requests made with the returned context as safe to
+// retry, even though their method is not idempotent.
+func WithRetrySafe(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retrySafeKey{}, true)
+}
+
+// RetryTransport retries requests failing with a 5xx or 429 response or a
+// connection reset, waiting with exponential backoff and jitter between the
+// attempts, or as long as the Retry-After response header asks for. Only
+// idempotent requests, and requests marked with WithRetrySafe, are retried.
+type RetryTransport struct {
+	Base http.RoundTripper
+
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// MinWait and MaxWait bound the wait between two attempts.
+	MinWait time.Duration
+	MaxWait time.Duration
+}
+
+// NewRetryTransport returns a RetryTransport retrying the requests of base up
+// to maxRetries times, waiting at most maxWait between two attempts.
+func NewRetryTransport(base http.RoundTripper, maxRetries int, maxWait time.Duration) *RetryTransport {
+	return &RetryTransport{
+		Base:       base,
+		MaxRetries: maxRetries,
+		MinWait:    min(defaultRetryMinWait, maxWait),
+		MaxWait:    maxWait,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.MaxRetries <= 0 || !retryable(req) {
+		return t.Base.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		res, err := t.Base.RoundTrip(attemptReq)
+		if attempt >= t.MaxRetries || !shouldRetry(res, err) {
+			return res, err
+		}
+
+		wait := t.backoff(attempt)
+		if res != nil {
+			if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+				wait = min(retryAfter, t.MaxWait)
+			}
+			// Drain the body so that the connection can be reused.
+			_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 4096))
+			res.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the wait before the retry following the attempt, doubling
+// with every attempt and picked at random in its upper half.
+func (t *RetryTransport) backoff(attempt int) time.Duration {
+	wait := t.MaxWait
+	if attempt < 32 {
+		wait = min(t.MinWait<<attempt, t.MaxWait)
+	}
+	if wait <= 1 {
+		return wait
+	}
+	return wait/2 + rand.N(wait/2) //nolint:gosec // jitter does not need a secure source
+}
+
+// retryable reports whether the request may be sent more than once.
+func retryable(req *http.Request) bool {
+	if safe, _ := req.Context().Value(retrySafeKey{}).(bool); safe {
+		return true
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	// The body of a retried request is replayed with GetBody.
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// shouldRetry reports whether the outcome of an attempt is transient.
+func shouldRetry(res *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+			errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return true
+		}
+		var netErr net.Error
+		return errors.As(err, &netErr) && netErr.Timeout()
+	}
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError &&
+		res.StatusCode != http.StatusNotImplemented
+}
+
+// parseRetryAfter parses a Retry-After header holding either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,170 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	testCases := map[string]struct {
+		method       string
+		safe         bool
+		statuses     []int
+		wantStatus   int
+		wantAttempts int32
+	}{
+		"get-server-error": {
+			method:       http.MethodGet,
+			statuses:     []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			wantStatus:   http.StatusOK,
+			wantAttempts: 3,
+		},
+		"get-too-many-requests": {
+			method:       http.MethodGet,
+			statuses:     []int{http.StatusTooManyRequests, http.StatusOK},
+			wantStatus:   http.StatusOK,
+			wantAttempts: 2,
+		},
+		"get-exhausted": {
+			method:       http.MethodGet,
+			statuses:     []int{http.StatusInternalServerError},
+			wantStatus:   http.StatusInternalServerError,
+			wantAttempts: 4,
+		},
+		"get-client-error": {
+			method:       http.MethodGet,
+			statuses:     []int{http.StatusNotFound},
+			wantStatus:   http.StatusNotFound,
+			wantAttempts: 1,
+		},
+		"delete-server-error": {
+			method:       http.MethodDelete,
+			statuses:     []int{http.StatusBadGateway, http.StatusNoContent},
+			wantStatus:   http.StatusNoContent,
+			wantAttempts: 2,
+		},
+		"post-server-error": {
+			method:       http.MethodPost,
+			statuses:     []int{http.StatusBadGateway, http.StatusOK},
+			wantStatus:   http.StatusBadGateway,
+			wantAttempts: 1,
+		},
+		"post-marked-safe": {
+			method:       http.MethodPost,
+			safe:         true,
+			statuses:     []int{http.StatusBadGateway, http.StatusOK},
+			wantStatus:   http.StatusOK,
+			wantAttempts: 2,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempt := int(attempts.Add(1)) - 1
+				if body, _ := io.ReadAll(r.Body); r.Method != http.MethodGet && string(body) != "payload" {
+					t.Errorf("attempt %d: unexpected body %q", attempt, body)
+				}
+				w.WriteHeader(testCase.statuses[min(attempt, len(testCase.statuses)-1)])
+			}))
+			defer server.Close()
+
+			transport := &RetryTransport{Base: http.DefaultTransport, MaxRetries: 3, MinWait: time.Millisecond, MaxWait: 5 * time.Millisecond}
+
+			ctx := context.Background()
+			if testCase.safe {
+				ctx = WithRetrySafe(ctx)
+			}
+			var body io.Reader
+			if testCase.method != http.MethodGet {
+				body = strings.NewReader("payload")
+			}
+			req, err := http.NewRequestWithContext(ctx, testCase.method, server.URL, body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := (&http.Client{Transport: transport}).Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			res.Body.Close()
+
+			if res.StatusCode != testCase.wantStatus {
+				t.Errorf("expected status %d, got %d", testCase.wantStatus, res.StatusCode)
+			}
+			if got := attempts.Load(); got != testCase.wantAttempts {
+				t.Errorf("expected %d attempts, got %d", testCase.wantAttempts, got)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_RetryAfter(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer server.Close()
+
+	transport := &RetryTransport{Base: http.DefaultTransport, MaxRetries: 1, MinWait: time.Millisecond, MaxWait: time.Minute}
+
+	start := time.Now()
+	res, err := (&http.Client{Transport: transport}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	res.Body.Close()
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected to wait for Retry-After, retried after %s", elapsed)
+	}
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", res.StatusCode)
+	}
+}
+
+func TestRetryTransport_ContextCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	transport := &RetryTransport{Base: http.DefaultTransport, MaxRetries: 3, MinWait: time.Hour, MaxWait: time.Hour}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := (&http.Client{Transport: transport}).Do(req); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if wait, ok := parseRetryAfter("7"); !ok || wait != 7*time.Second {
+		t.Errorf("expected 7s, got %s, %t", wait, ok)
+	}
+	if wait, ok := parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)); !ok || wait < 59*time.Minute {
+		t.Errorf("expected about 1h, got %s, %t", wait, ok)
+	}
+	if _, ok := parseRetryAfter("soon"); ok {
+		t.Error("expected invalid value to be ignored")
+	}
+}
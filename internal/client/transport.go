@@ -0,0 +1,111 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// TransportOptions configure the TLS and proxy settings of the HTTP
+// transport shared by every API client.
+type TransportOptions struct {
+	// CABundle is a PEM encoded bundle, or the path of one, of certificate
+	// authorities trusted in addition to the system ones.
+	CABundle string
+	// Insecure disables the verification of the server certificate.
+	Insecure bool
+	// HTTPProxy is the URL of the proxy requests are sent through. When
+	// empty the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables
+	// are honored.
+	HTTPProxy string
+	// ClientCertificate and ClientKey are a PEM encoded client certificate
+	// and private key, or the paths of them, presented to the server.
+	ClientCertificate string
+	ClientKey         string
+}
+
+// NewTransport returns an HTTP transport configured with the options.
+func NewTransport(options TransportOptions) (*http.Transport, error) {
+	defaultTransport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("unexpected default transport type %T", http.DefaultTransport)
+	}
+	transport := defaultTransport.Clone()
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if options.CABundle != "" {
+		bundle, err := readPEM(options.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA bundle: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, errors.New("CA bundle does not contain any PEM encoded certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if options.Insecure {
+		tlsConfig.InsecureSkipVerify = true //nolint:gosec // explicitly requested for lab environments
+	}
+
+	if options.ClientCertificate != "" || options.ClientKey != "" {
+		if options.ClientCertificate == "" || options.ClientKey == "" {
+			return nil, errors.New("client certificate and client key must be set together")
+		}
+
+		certificate, err := readPEM(options.ClientCertificate)
+		if err != nil {
+			return nil, fmt.Errorf("reading client certificate: %w", err)
+		}
+		key, err := readPEM(options.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("reading client key: %w", err)
+		}
+
+		keyPair, err := tls.X509KeyPair(certificate, key)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{keyPair}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	if options.HTTPProxy != "" {
+		proxyURL, err := url.Parse(options.HTTPProxy)
+		if err != nil {
+			return nil, fmt.Errorf("parsing HTTP proxy: %w", err)
+		}
+		if proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("HTTP proxy must be an absolute URL, got %q", options.HTTPProxy)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	return transport, nil
+}
+
+// readPEM returns the value itself when it holds PEM data, or the content of
+// the file it names otherwise.
+func readPEM(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN ") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
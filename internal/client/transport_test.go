@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewTransport_CABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	bundle := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	bundleFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(bundleFile, []byte(bundle), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		options TransportOptions
+		wantErr bool
+	}{
+		"system-roots": {
+			options: TransportOptions{},
+			wantErr: true,
+		},
+		"pem": {
+			options: TransportOptions{CABundle: bundle},
+		},
+		"file": {
+			options: TransportOptions{CABundle: bundleFile},
+		},
+		"insecure": {
+			options: TransportOptions{Insecure: true},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			transport, err := NewTransport(testCase.options)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			res, err := (&http.Client{Transport: transport}).Get(server.URL)
+			if testCase.wantErr {
+				if err == nil {
+					res.Body.Close()
+					t.Fatal("expected certificate verification error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			res.Body.Close()
+		})
+	}
+}
+
+func TestNewTransport_HTTPProxy(t *testing.T) {
+	transport, err := NewTransport(TransportOptions{HTTPProxy: "http://proxy.internal:3128"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	proxyURL, err := transport.Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "api.viettelidc.com.vn"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if proxyURL == nil || proxyURL.Host != "proxy.internal:3128" {
+		t.Errorf("expected proxy.internal:3128, got %v", proxyURL)
+	}
+}
+
+func TestNewTransport_Invalid(t *testing.T) {
+	for name, options := range map[string]TransportOptions{
+		"ca-bundle-missing-file": {CABundle: filepath.Join(t.TempDir(), "missing.pem")},
+		"ca-bundle-no-pem":       {CABundle: "-----BEGIN CERTIFICATE-----\nnot a certificate\n-----END CERTIFICATE-----\n"},
+		"client-key-only":        {ClientKey: "key.pem"},
+		"relative-proxy":         {HTTPProxy: "proxy.internal:3128"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewTransport(options); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package voksapi extends the generated vOKS API client with the operations
+// its current release, service/voks v1.0.1, does not provide yet. They are
+// sent like the generated operations: with the configuration, and so the
+// HTTP client and access token, of the client they extend, and failing with
+// an error keeping the response body.
+package voksapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/viettelidc-provider/viettelidc-api-client-go/service/voks"
+	"github.com/viettelidc-provider/viettelidc-api-client-go/viettelidc"
+)
+
+// Client is the generated vOKS API client, extended with the operations it
+// lacks.
+type Client struct {
+	*voks.APIClient
+
+	cfg *viettelidc.Configuration
+}
+
+// NewClient returns a Client sending its requests with cfg.
+func NewClient(cfg *viettelidc.Configuration) *Client {
+	return &Client{
+		APIClient: voks.NewAPIClient(*cfg),
+		cfg:       cfg,
+	}
+}
+
+// Error is a failed call, reported like the generated clients do: with the
+// response status as message and the response body kept.
+type Error struct {
+	status string
+	body   []byte
+}
+
+func (e *Error) Error() string {
+	return e.status
+}
+
+// Body returns the body of the error response.
+func (e *Error) Body() []byte {
+	return e.body
+}
+
+// call sends a request with the JSON encoded body, when not nil, and decodes
+// the response body into out, when not nil.
+func (c *Client) call(ctx context.Context, method, path string, body, out any) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.cfg.BasePath, "/")+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.cfg.UserAgent != "" {
+		req.Header.Set("User-Agent", c.cfg.UserAgent)
+	}
+	for name, value := range c.cfg.DefaultHeader {
+		req.Header.Set(name, value)
+	}
+	if c.cfg.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.AccessToken)
+	}
+
+	httpClient := c.cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return res, err
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return res, err
+	}
+	if res.StatusCode >= http.StatusMultipleChoices {
+		return res, &Error{status: res.Status, body: resBody}
+	}
+	if out != nil && len(resBody) > 0 {
+		if err := json.Unmarshal(resBody, out); err != nil {
+			return res, &Error{status: err.Error(), body: resBody}
+		}
+	}
+	return res, nil
+}
+
+// VpcConfig is the network of a Cluster.
+type VpcConfig struct {
+	VpcId            int32   `json:"vpcId"`
+	SecurityGroupIds []int32 `json:"securityGroupIds,omitempty"`
+	SubnetIds        []int32 `json:"subnetIds,omitempty"`
+}
+
+// ClusterDetail is a Cluster, with the control plane type the generated
+// client does not decode.
+type ClusterDetail struct {
+	Id                       int32     `json:"id"`
+	Name                     string    `json:"name"`
+	Status                   string    `json:"status"`
+	Version                  string    `json:"version"`
+	ApiAddress               string    `json:"apiAddress"`
+	ControlPlaneResourceType string    `json:"controlPlaneResourceType"`
+	VpcConfig                VpcConfig `json:"vpcConfig"`
+}
+
+// CreateClusterRequest creates a Cluster, with its NFS Storage when
+// EnableNfs is set.
+type CreateClusterRequest struct {
+	Name                     string    `json:"name"`
+	Version                  string    `json:"version"`
+	VpcConfig                VpcConfig `json:"vpcConfig"`
+	ControlPlaneResourceType string    `json:"controlPlaneResourceType,omitempty"`
+	EnableNfs                bool      `json:"enableNfs"`
+	AddOnsStorage            int32     `json:"addOnsStorage,omitempty"`
+}
+
+// ClusterResponse identifies the Cluster a request created.
+type ClusterResponse struct {
+	Id int32 `json:"id"`
+}
+
+// DetailCluster reads a Cluster.
+func (c *Client) DetailCluster(ctx context.Context, clusterId int32) (ClusterDetail, *http.Response, error) {
+	var cluster ClusterDetail
+	res, err := c.call(ctx, http.MethodGet, fmt.Sprintf("/voks/v1/clusters/%d", clusterId), nil, &cluster)
+	return cluster, res, err
+}
+
+// CreateCluster starts the creation of a Cluster.
+func (c *Client) CreateCluster(ctx context.Context, body CreateClusterRequest) (ClusterResponse, *http.Response, error) {
+	var cluster ClusterResponse
+	res, err := c.call(ctx, http.MethodPost, "/voks/v1/clusters", body, &cluster)
+	return cluster, res, err
+}
+
+// DeleteCluster starts the deletion of a Cluster, which must not have Node
+// Groups left.
+func (c *Client) DeleteCluster(ctx context.Context, body voks.BaseResourceReq) (*http.Response, error) {
+	return c.call(ctx, http.MethodDelete, "/voks/v1/clusters", body, nil)
+}
+
+// DeleteNfsStorage starts the deletion of the NFS Storage of a Cluster.
+func (c *Client) DeleteNfsStorage(ctx context.Context, body voks.BaseResourceReq) (*http.Response, error) {
+	return c.call(ctx, http.MethodPost, "/voks/v1/nfs/delete", body, nil)
+}
+
+// NodeGroupSummary is a Node Group as listed with the others of its Cluster.
+type NodeGroupSummary struct {
+	Id     int32  `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// GetAllNodeGroup lists the Node Groups of a Cluster.
+func (c *Client) GetAllNodeGroup(ctx context.Context, clusterId int32) ([]NodeGroupSummary, *http.Response, error) {
+	var nodeGroups []NodeGroupSummary
+	res, err := c.call(ctx, http.MethodGet, fmt.Sprintf("/voks/v1/clusters/%d/node-groups", clusterId), nil, &nodeGroups)
+	return nodeGroups, res, err
+}
+
+// InstalledAddon is an Addon installed on a Cluster.
+type InstalledAddon struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Version string `json:"version"`
+}
+
+// GetAllInstalledAddon lists the Addons installed on a Cluster.
+func (c *Client) GetAllInstalledAddon(ctx context.Context, clusterId int32) ([]InstalledAddon, *http.Response, error) {
+	var addons []InstalledAddon
+	res, err := c.call(ctx, http.MethodGet, fmt.Sprintf("/voks/v1/clusters/%d/addons", clusterId), nil, &addons)
+	return addons, res, err
+}
+
+// UpgradeClusterRequest upgrades the control plane of a Cluster to Version.
+type UpgradeClusterRequest struct {
+	ClusterId int32  `json:"clusterId"`
+	Version   string `json:"version"`
+}
+
+// UpgradeCluster starts the upgrade of the control plane of a Cluster.
+func (c *Client) UpgradeCluster(ctx context.Context, body UpgradeClusterRequest) (*http.Response, error) {
+	return c.call(ctx, http.MethodPost, "/voks/v1/clusters/upgrade", body, nil)
+}
+
+// UpgradeNodeGroupRequest upgrades a Node Group to the version of its
+// Cluster.
+type UpgradeNodeGroupRequest struct {
+	ClusterId int32 `json:"clusterId"`
+	Id        int32 `json:"id"`
+}
+
+// UpgradeNodeGroup starts the upgrade of a Node Group.
+func (c *Client) UpgradeNodeGroup(ctx context.Context, body UpgradeNodeGroupRequest) (*http.Response, error) {
+	return c.call(ctx, http.MethodPost, "/voks/v1/node-groups/upgrade", body, nil)
+}
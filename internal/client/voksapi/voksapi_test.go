@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package voksapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"terraform-provider-viettelidc/internal/client"
+	"terraform-provider-viettelidc/internal/client/voksapi"
+	"testing"
+
+	"github.com/viettelidc-provider/viettelidc-api-client-go/viettelidc"
+)
+
+func TestClient_CreateCluster(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/voks/v1/clusters" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("expected the access token to be sent, got: %q", got)
+		}
+		var body voksapi.CreateClusterRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("could not decode the request body: %s", err)
+		}
+		if body.Name != "test" || len(body.VpcConfig.SubnetIds) != 1 {
+			t.Errorf("unexpected request body: %+v", body)
+		}
+		_, _ = w.Write([]byte(`{"id": 42}`))
+	}))
+	defer server.Close()
+
+	apiClient := voksapi.NewClient(&viettelidc.Configuration{
+		BasePath:    server.URL,
+		AccessToken: "token",
+		HTTPClient:  server.Client(),
+	})
+	cluster, _, err := apiClient.CreateCluster(context.Background(), voksapi.CreateClusterRequest{
+		Name:      "test",
+		VpcConfig: voksapi.VpcConfig{VpcId: 1, SubnetIds: []int32{2}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cluster.Id != 42 {
+		t.Errorf("expected cluster 42, got: %d", cluster.Id)
+	}
+}
+
+func TestClient_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"code": "CLUSTER_NOT_FOUND", "message": "Cluster 42 not found"}`))
+	}))
+	defer server.Close()
+
+	apiClient := voksapi.NewClient(&viettelidc.Configuration{
+		BasePath:   server.URL,
+		HTTPClient: server.Client(),
+	})
+	_, httpRes, err := apiClient.DetailCluster(context.Background(), 42)
+	if !client.IsNotFound(httpRes, err) {
+		t.Fatalf("expected a not found error, got: %v", err)
+	}
+	var apiErr *client.APIError
+	if !errors.As(client.NewAPIError(httpRes, err), &apiErr) || apiErr.Code != "CLUSTER_NOT_FOUND" {
+		t.Errorf("expected the error body to be decoded, got: %+v", apiErr)
+	}
+}
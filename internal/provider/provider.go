@@ -5,7 +5,19 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"terraform-provider-viettelidc/internal/auth"
+	"terraform-provider-viettelidc/internal/client"
+	regionDatasource "terraform-provider-viettelidc/internal/service/region/datasource"
 	voksDatasource "terraform-provider-viettelidc/internal/service/voks/datasource"
 	voksResource "terraform-provider-viettelidc/internal/service/voks/resource"
 	vpcDatasource "terraform-provider-viettelidc/internal/service/vpc/datasource"
@@ -13,12 +25,15 @@ import (
 	"github.com/viettelidc-provider/viettelidc-api-client-go/service/iam"
 	"github.com/viettelidc-provider/viettelidc-api-client-go/viettelidc"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -44,10 +59,45 @@ type viettelidcProvider struct {
 }
 
 type viettelidcProviderModel struct {
-	DomainId types.String `tfsdk:"domain_id"`
-	Username types.String `tfsdk:"username"`
-	Password types.String `tfsdk:"password"`
-	MfaCode  types.String `tfsdk:"mfa_code"`
+	Host          types.String    `tfsdk:"host"`
+	Region        types.String    `tfsdk:"region"`
+	Regions       types.Map       `tfsdk:"regions"`
+	Endpoints     *endpointsModel `tfsdk:"endpoints"`
+	DomainId      types.String    `tfsdk:"domain_id"`
+	Username      types.String    `tfsdk:"username"`
+	Password      types.String    `tfsdk:"password"`
+	MfaCode       types.String    `tfsdk:"mfa_code"`
+	MfaTotpSecret types.String    `tfsdk:"mfa_totp_secret"`
+	AccessToken   types.String    `tfsdk:"access_token"`
+
+	Profile               types.String `tfsdk:"profile"`
+	SharedCredentialsFile types.String `tfsdk:"shared_credentials_file"`
+	TokenCache            types.Bool   `tfsdk:"token_cache"`
+
+	CaBundle          types.String `tfsdk:"ca_bundle"`
+	Insecure          types.Bool   `tfsdk:"insecure"`
+	HttpProxy         types.String `tfsdk:"http_proxy"`
+	ClientCertificate types.String `tfsdk:"client_certificate"`
+	ClientKey         types.String `tfsdk:"client_key"`
+
+	MaxRetries   types.Int64  `tfsdk:"max_retries"`
+	RetryMaxWait types.String `tfsdk:"retry_max_wait"`
+
+	MaxRequestsPerSecond  types.Float64 `tfsdk:"max_requests_per_second"`
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
+}
+
+type regionModel struct {
+	Name     types.String      `tfsdk:"name"`
+	Endpoint types.String      `tfsdk:"endpoint"`
+	HostId   types.Int32       `tfsdk:"host_id"`
+	Zones    map[string]string `tfsdk:"zones"`
+}
+
+type endpointsModel struct {
+	Iam  types.String `tfsdk:"iam"`
+	Voks types.String `tfsdk:"voks"`
+	Vpc  types.String `tfsdk:"vpc"`
 }
 
 // Metadata returns the provider type name.
@@ -61,6 +111,39 @@ func (p *viettelidcProvider) Schema(_ context.Context, _ provider.SchemaRequest,
 	resp.Schema = schema.Schema{
 		Description: "Interact with ViettelIdc resource.",
 		Attributes: map[string]schema.Attribute{
+			"host": schema.StringAttribute{
+				Description: "Base URL of the ViettelIdc API. Defaults to `https://api.viettelidc.com.vn`.",
+				Optional:    true,
+			},
+			"region": schema.StringAttribute{
+				Description: "Region of the ViettelIdc resources, one of the codes declared in `regions`. Selects the API gateway of the region for the vOKS and VPC APIs unless `host` or their `endpoints` are set, the IAM API stays on `host`. Resources can override it with their own `region` argument.",
+				Optional:    true,
+			},
+			"regions": schema.MapNestedAttribute{
+				Description: "Regions the provider and its resources can target, keyed by the code set in the `region` arguments. The ViettelIdc API does not list its regions, declare them with the API gateways and data centers shown in the ViettelIdc portal.",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "Name of the region.",
+							Optional:    true,
+						},
+						"endpoint": schema.StringAttribute{
+							Description: "Base URL of the API gateway of the region, serving its vOKS and VPC APIs.",
+							Required:    true,
+						},
+						"host_id": schema.Int32Attribute{
+							Description: "Host ID of the region in the VPC API, listed by `viettelidc_vpcs` when its own `host_id` is not set.",
+							Optional:    true,
+						},
+						"zones": schema.MapAttribute{
+							Description: "Availability zones of the region, the name of their data center keyed by their code.",
+							ElementType: types.StringType,
+							Optional:    true,
+						},
+					},
+				},
+			},
 			"domain_id": schema.StringAttribute{
 				Description: "DomainId for ViettelIdc API.",
 				Optional:    true,
@@ -77,6 +160,84 @@ func (p *viettelidcProvider) Schema(_ context.Context, _ provider.SchemaRequest,
 				Description: "Muti-factor Authentication code for ViettelIdc API.",
 				Optional:    true,
 			},
+			"mfa_totp_secret": schema.StringAttribute{
+				Description: "Base32 secret of the Muti-factor Authentication device for ViettelIdc API, as shown below the QR code in the ViettelIdc portal. When set, the MFA code is generated at login time and `mfa_code` is ignored.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"access_token": schema.StringAttribute{
+				Description: "Access token for ViettelIdc API. When set, the login flow is skipped and `username`, `password` and `mfa_code` are ignored.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"profile": schema.StringAttribute{
+				Description: "Name of the profile in the shared credentials file to read credentials and endpoints from. Defaults to `default`.",
+				Optional:    true,
+			},
+			"shared_credentials_file": schema.StringAttribute{
+				Description: "Path of the shared credentials file. Defaults to `~/.viettelidc/credentials`.",
+				Optional:    true,
+			},
+			"token_cache": schema.BoolAttribute{
+				Description: "Default to `false`. Set it to `true` to cache the access token in `~/.viettelidc/cache`, so that consecutive Terraform commands reuse a single login until the token expires.",
+				Optional:    true,
+			},
+			"ca_bundle": schema.StringAttribute{
+				Description: "PEM encoded certificate authorities, or the path of a file holding them, trusted in addition to the system ones when connecting to the ViettelIdc API, e.g. behind a TLS inspecting proxy.",
+				Optional:    true,
+			},
+			"insecure": schema.BoolAttribute{
+				Description: "Default to `false`. Set it to `true` to skip the verification of the ViettelIdc API server certificate. Only use it against lab environments.",
+				Optional:    true,
+			},
+			"http_proxy": schema.StringAttribute{
+				Description: "URL of the proxy to send the ViettelIdc API requests through. Defaults to the `HTTPS_PROXY`, `HTTP_PROXY` and `NO_PROXY` environment variables.",
+				Optional:    true,
+			},
+			"client_certificate": schema.StringAttribute{
+				Description: "PEM encoded client certificate, or the path of a file holding it, presented to the ViettelIdc API for mutual TLS. Requires `client_key`.",
+				Optional:    true,
+			},
+			"client_key": schema.StringAttribute{
+				Description: "PEM encoded private key of `client_certificate`, or the path of a file holding it.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"max_retries": schema.Int64Attribute{
+				Description: "Default to `4`. Number of times a request failing with a 5xx or 429 response or a connection reset is retried. Only idempotent requests are retried. Set it to `0` to disable retries.",
+				Optional:    true,
+			},
+			"retry_max_wait": schema.StringAttribute{
+				Description: "Default to `30s`. Longest wait between two attempts of a request, as a duration such as `1m`. Waits grow exponentially up to it, or follow the `Retry-After` response header.",
+				Optional:    true,
+			},
+			"max_requests_per_second": schema.Float64Attribute{
+				Description: "Default to `10`. Number of ViettelIdc API requests per second the provider sends at most, across every resource and data source. Set it to `0` to lift the limit.",
+				Optional:    true,
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Description: "Default to `5`. Number of ViettelIdc API requests in flight at a time, across every resource and data source. Set it to `0` to lift the limit.",
+				Optional:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"endpoints": schema.SingleNestedBlock{
+				Description: "Override the base URL of individual ViettelIdc API services, e.g. to target a staging gateway or a local mock server. Services without an override use `host`, or the API gateway of the `region` for the vOKS and VPC APIs when `host` is not set.",
+				Attributes: map[string]schema.Attribute{
+					"iam": schema.StringAttribute{
+						Description: "Base URL of the IAM API, used to log in and read the account information.",
+						Optional:    true,
+					},
+					"voks": schema.StringAttribute{
+						Description: "Base URL of the vOKS API.",
+						Optional:    true,
+					},
+					"vpc": schema.StringAttribute{
+						Description: "Base URL of the VPC API.",
+						Optional:    true,
+					},
+				},
+			},
 		},
 	}
 }
@@ -91,57 +252,65 @@ func (p *viettelidcProvider) Configure(ctx context.Context, req provider.Configu
 		return
 	}
 
-	// If practitioner provided a configuration value for any of the
-	// attributes, it must be a known value.
+	// Values unknown until apply, e.g. credentials read from another
+	// resource or a vault data source, do not fail the operations which
+	// never call the API. Terraform defers the others when it supports it,
+	// otherwise the client reports the unknown values on first use.
+	if unknown := config.unknownAttributes(ctx); len(unknown) > 0 {
+		tflog.Debug(ctx, "Viettelidc provider configuration is unknown, API calls are unavailable", map[string]any{"attributes": unknown})
+
+		if req.ClientCapabilities.DeferralAllowed {
+			resp.Deferred = &provider.Deferred{
+				Reason: provider.DeferredReasonProviderConfigUnknown,
+			}
+		}
 
-	if config.DomainId.IsUnknown() {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("username"),
-			"Unknown Viettelidc API DomainId",
-			"The provider cannot create the Viettelidc API client as there is an unknown configuration value for the Viettelidc API domain_id. "+
-				"Either target apply the source of the value first, set the value statically in the configuration, or use the VIETTELIDC_DOMAIN_ID environment variable.",
-		)
+		providerClient := client.NewUnknownClient(unknown)
+		resp.DataSourceData = providerClient
+		resp.ResourceData = providerClient
+		return
 	}
 
-	if config.Username.IsUnknown() {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("password"),
-			"Unknown Viettelidc API Username",
-			"The provider cannot create the Viettelidc API client as there is an unknown configuration value for the Viettelidc API username. "+
-				"Either target apply the source of the value first, set the value statically in the configuration, or use the VIETTELIDC_USERNAME environment variable.",
-		)
+	profile, diags := loadProfile(config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	if config.Password.IsUnknown() {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("password"),
-			"Unknown Viettelidc API Username",
-			"The provider cannot create the Viettelidc API client as there is an unknown configuration value for the Viettelidc API password. "+
-				"Either target apply the source of the value first, set the value statically in the configuration, or use the VIETTELIDC_PASSWORD environment variable.",
-		)
-	}
+	// Default values to the shared credentials profile, override them with
+	// environment variables and finally with Terraform configuration value
+	// if set.
 
-	if config.MfaCode.IsUnknown() {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("password"),
-			"Unknown Viettelidc API Username",
-			"The provider cannot create the Viettelidc API client as there is an unknown configuration value for the Viettelidc API MFA Code. "+
-				"Either target apply the source of the value first, set the value statically in the configuration, or use the VIETTELIDC_MFA_CODE environment variable.",
-		)
+	host := getEnv("VIETTELIDC_HOST", profile.Host)
+	region := getEnv("VIETTELIDC_REGION", profile.Region)
+	domainId := getEnv("VIETTELIDC_DOMAIN_ID", profile.DomainId)
+	username := getEnv("VIETTELIDC_USERNAME", profile.Username)
+	password := getEnv("VIETTELIDC_PASSWORD", profile.Password)
+	mfaCode := os.Getenv("VIETTELIDC_MFA_CODE")
+	mfaTotpSecret := getEnv("VIETTELIDC_MFA_TOTP_SECRET", profile.MfaTotpSecret)
+	accessToken := getEnv("VIETTELIDC_ACCESS_TOKEN", profile.AccessToken)
+
+	endpoints := map[string]string{
+		"iam":  getEnv("VIETTELIDC_IAM_ENDPOINT", profile.IamEndpoint),
+		"voks": getEnv("VIETTELIDC_VOKS_ENDPOINT", profile.VoksEndpoint),
+		"vpc":  getEnv("VIETTELIDC_VPC_ENDPOINT", profile.VpcEndpoint),
 	}
 
-	if resp.Diagnostics.HasError() {
-		return
+	if !config.Host.IsNull() {
+		host = config.Host.ValueString()
 	}
 
-	// Default values to environment variables, but override
-	// with Terraform configuration value if set.
+	if !config.Region.IsNull() {
+		region = config.Region.ValueString()
+	}
 
-	host := os.Getenv("VIETTELIDC_HOST")
-	domainId := os.Getenv("VIETTELIDC_DOMAIN_ID")
-	username := os.Getenv("VIETTELIDC_USERNAME")
-	password := os.Getenv("VIETTELIDC_PASSWORD")
-	mfaCode := os.Getenv("VIETTELIDC_MFA_CODE")
+	if config.Endpoints != nil {
+		for name, endpoint := range config.Endpoints.values() {
+			if !endpoint.IsNull() {
+				endpoints[name] = endpoint.ValueString()
+			}
+		}
+	}
 
 	if !config.DomainId.IsNull() {
 		domainId = config.DomainId.ValueString()
@@ -159,40 +328,201 @@ func (p *viettelidcProvider) Configure(ctx context.Context, req provider.Configu
 		mfaCode = config.MfaCode.ValueString()
 	}
 
+	if !config.MfaTotpSecret.IsNull() {
+		mfaTotpSecret = config.MfaTotpSecret.ValueString()
+	}
+
+	if !config.AccessToken.IsNull() {
+		accessToken = config.AccessToken.ValueString()
+	}
+
+	useTokenCache, diags := getEnvBool("token_cache", false)
+	resp.Diagnostics.Append(diags...)
+	if !config.TokenCache.IsNull() {
+		useTokenCache = config.TokenCache.ValueBool()
+	}
+
+	transportOptions := client.TransportOptions{
+		CABundle:          os.Getenv("VIETTELIDC_CA_BUNDLE"),
+		HTTPProxy:         os.Getenv("VIETTELIDC_HTTP_PROXY"),
+		ClientCertificate: os.Getenv("VIETTELIDC_CLIENT_CERTIFICATE"),
+		ClientKey:         os.Getenv("VIETTELIDC_CLIENT_KEY"),
+	}
+	transportOptions.Insecure, diags = getEnvBool("insecure", false)
+	resp.Diagnostics.Append(diags...)
+
+	if !config.CaBundle.IsNull() {
+		transportOptions.CABundle = config.CaBundle.ValueString()
+	}
+
+	if !config.Insecure.IsNull() {
+		transportOptions.Insecure = config.Insecure.ValueBool()
+	}
+
+	if !config.HttpProxy.IsNull() {
+		transportOptions.HTTPProxy = config.HttpProxy.ValueString()
+	}
+
+	if !config.ClientCertificate.IsNull() {
+		transportOptions.ClientCertificate = config.ClientCertificate.ValueString()
+	}
+
+	if !config.ClientKey.IsNull() {
+		transportOptions.ClientKey = config.ClientKey.ValueString()
+	}
+
+	maxRetries, diags := getEnvInt64("max_retries", client.DefaultMaxRetries)
+	resp.Diagnostics.Append(diags...)
+	if !config.MaxRetries.IsNull() {
+		maxRetries = config.MaxRetries.ValueInt64()
+	}
+
+	retryMaxWait := getEnv("VIETTELIDC_RETRY_MAX_WAIT", client.DefaultRetryMaxWait.String())
+	if !config.RetryMaxWait.IsNull() {
+		retryMaxWait = config.RetryMaxWait.ValueString()
+	}
+
+	maxRequestsPerSecond, diags := getEnvFloat64("max_requests_per_second", client.DefaultMaxRequestsPerSecond)
+	resp.Diagnostics.Append(diags...)
+	if !config.MaxRequestsPerSecond.IsNull() {
+		maxRequestsPerSecond = config.MaxRequestsPerSecond.ValueFloat64()
+	}
+
+	maxConcurrentRequests, diags := getEnvInt64("max_concurrent_requests", client.DefaultMaxConcurrentRequests)
+	resp.Diagnostics.Append(diags...)
+	if !config.MaxConcurrentRequests.IsNull() {
+		maxConcurrentRequests = config.MaxConcurrentRequests.ValueInt64()
+	}
+
 	// If any of the expected configurations are missing, return
 	// errors with provider-specific guidance.
 
+	regions, diags := config.regions(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if region != "" && !diags.HasError() {
+		regionInfo, err := client.LookupRegion(regions, region)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("region"),
+				"Invalid Viettelidc Region",
+				"The provider cannot create the Viettelidc API client as the region is not valid. "+
+					"Set the region value in the configuration or use the VIETTELIDC_REGION environment variable to one of the regions declared in the regions value.\n\n"+
+					"Error: "+err.Error(),
+			)
+		} else if host == "" {
+			// The IAM API is global, only the vOKS and VPC APIs are served
+			// by the API gateway of the region.
+			for _, name := range []string{"voks", "vpc"} {
+				if endpoints[name] == "" {
+					endpoints[name] = regionInfo.Endpoint
+				}
+			}
+		}
+	}
+
 	if host == "" {
 		host = "https://api.viettelidc.com.vn"
 	}
 
-	if domainId == "" {
+	if err := validateEndpoint(host); err != nil {
 		resp.Diagnostics.AddAttributeError(
-			path.Root("domain_id"),
-			"Missing Viettelidc API DomainId",
-			"The provider cannot create the Viettelidc API client as there is a missing or empty value for the Viettelidc API domainId. "+
-				"Set the username value in the configuration or use the VIETTELIDC_DOMAIN_ID environment variable. "+
-				"If either is already set, ensure the value is not empty.",
+			path.Root("host"),
+			"Invalid Viettelidc API Host",
+			"The provider cannot create the Viettelidc API client as the Viettelidc API host is not a valid URL. "+
+				"Set the host value in the configuration or use the VIETTELIDC_HOST environment variable.\n\n"+
+				"Error: "+err.Error(),
 		)
 	}
 
-	if username == "" {
+	for name, endpoint := range endpoints {
+		if endpoint == "" {
+			endpoints[name] = host
+			continue
+		}
+		if err := validateEndpoint(endpoint); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("endpoints").AtName(name),
+				"Invalid Viettelidc API Endpoint",
+				fmt.Sprintf("The provider cannot create the Viettelidc API client as the Viettelidc %s API endpoint is not a valid URL. ", name)+
+					fmt.Sprintf("Set the endpoint in the configuration or use the VIETTELIDC_%s_ENDPOINT environment variable.\n\n", strings.ToUpper(name))+
+					"Error: "+err.Error(),
+			)
+		}
+	}
+
+	// Username and password are only needed when no static access
+	// token has been supplied.
+	if accessToken == "" {
+		if domainId == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("domain_id"),
+				"Missing Viettelidc API DomainId",
+				"The provider cannot create the Viettelidc API client as there is a missing or empty value for the Viettelidc API domainId. "+
+					"Set the username value in the configuration or use the VIETTELIDC_DOMAIN_ID environment variable. "+
+					"If either is already set, ensure the value is not empty.",
+			)
+		}
+
+		if username == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("username"),
+				"Missing Viettelidc API Username",
+				"The provider cannot create the Viettelidc API client as there is a missing or empty value for the Viettelidc API username. "+
+					"Set the username value in the configuration or use the VIETTELIDC_USERNAME environment variable, or set an access token instead. "+
+					"If either is already set, ensure the value is not empty.",
+			)
+		}
+
+		if password == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("password"),
+				"Missing Viettelidc API Password",
+				"The provider cannot create the Viettelidc API client as there is a missing or empty value for the Viettelidc API password. "+
+					"Set the password value in the configuration or use the VIETTELIDC_PASSWORD environment variable. "+
+					"If either is already set, ensure the value is not empty.",
+			)
+		}
+	}
+
+	if maxRetries < 0 {
 		resp.Diagnostics.AddAttributeError(
-			path.Root("username"),
-			"Missing Viettelidc API Username",
-			"The provider cannot create the Viettelidc API client as there is a missing or empty value for the Viettelidc API username. "+
-				"Set the username value in the configuration or use the VIETTELIDC_USERNAME environment variable. "+
-				"If either is already set, ensure the value is not empty.",
+			path.Root("max_retries"),
+			"Invalid Viettelidc API Max Retries",
+			"The provider cannot create the Viettelidc API client as max_retries is negative. "+
+				"Set it to 0 to disable retries.",
 		)
 	}
 
-	if password == "" {
+	if maxRequestsPerSecond < 0 || maxConcurrentRequests < 0 {
+		resp.Diagnostics.AddError(
+			"Invalid Viettelidc API Request Limits",
+			"The provider cannot create the Viettelidc API client as max_requests_per_second or max_concurrent_requests is negative. "+
+				"Set them to 0 to lift the limits.",
+		)
+	}
+
+	retryMaxWaitDuration, err := time.ParseDuration(retryMaxWait)
+	if err == nil && retryMaxWaitDuration <= 0 {
+		err = fmt.Errorf("expected a positive duration, got %q", retryMaxWait)
+	}
+	if err != nil {
 		resp.Diagnostics.AddAttributeError(
-			path.Root("password"),
-			"Missing Viettelidc API Password",
-			"The provider cannot create the Viettelidc API client as there is a missing or empty value for the Viettelidc API password. "+
-				"Set the password value in the configuration or use the VIETTELIDC_PASSWORD environment variable. "+
-				"If either is already set, ensure the value is not empty.",
+			path.Root("retry_max_wait"),
+			"Invalid Viettelidc API Retry Max Wait",
+			"The provider cannot create the Viettelidc API client as retry_max_wait is not a valid duration. "+
+				"Set a duration such as 30s or 1m in the configuration or in the VIETTELIDC_RETRY_MAX_WAIT environment variable.\n\n"+
+				"Error: "+err.Error(),
+		)
+	}
+
+	transport, err := client.NewTransport(transportOptions)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Viettelidc API Transport Configuration",
+			"The provider cannot create the Viettelidc API client as the TLS or proxy settings are invalid. "+
+				"Check the ca_bundle, http_proxy, client_certificate and client_key values in the configuration and the matching VIETTELIDC_* environment variables.\n\n"+
+				"Error: "+err.Error(),
 		)
 	}
 
@@ -201,85 +531,467 @@ func (p *viettelidcProvider) Configure(ctx context.Context, req provider.Configu
 	}
 
 	configuration := &viettelidc.Configuration{
-		BasePath:      host,
+		BasePath:      endpoints["iam"],
 		DefaultHeader: make(map[string]string),
 		UserAgent:     "viettelidc/iac",
+		HTTPClient:    &http.Client{},
 	}
-	iamAPIClient := iam.NewAPIClient(configuration)
-
-	loginRes, _, err := iamAPIClient.AuthorizationControllerApi.LoginViaLoginPage(ctx, iam.LoginViaLoginPageRequest{
-		Username:     username,
-		Password:     password,
-		DomainId:     domainId,
-		IsRememberMe: false,
-		UserType:     "IAM_USER",
-	})
 
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Create Viettelidc API Client",
-			"An unexpected error occurred when creating the Viettelidc API client. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Viettelidc Client Error: "+err.Error(),
-		)
-		return
+	// Every attempt of every request, logins included, is logged and goes
+	// through a single limiter, so that the gateway is not throttling
+	// concurrent operations. Transient failures are retried above it, so
+	// that waiting for a retry does not hold a slot, and below the
+	// authenticating transport, which only sees the outcome of the last
+	// attempt.
+	limiter := client.NewLimiter(maxRequestsPerSecond, int(maxConcurrentRequests))
+	limitTransport := client.NewLimitTransport(client.NewLoggingTransport(transport), limiter)
+	retryTransport := client.NewRetryTransport(limitTransport, int(maxRetries), retryMaxWaitDuration)
+
+	// The login flow gets a client of its own, so that logging in again
+	// from the authenticating transport below never recurses into it. Both
+	// share the TLS and proxy settings, and the connection pool.
+	loginConfiguration := *configuration
+	loginConfiguration.HTTPClient = &http.Client{Transport: retryTransport}
+	loginAPIClient := iam.NewAPIClient(&loginConfiguration)
+
+	credentials := auth.Credentials{
+		DomainId:      domainId,
+		Username:      username,
+		Password:      password,
+		MfaCode:       mfaCode,
+		MfaTotpSecret: mfaTotpSecret,
 	}
 
-	if loginRes.IsRequiredSecondAuthenticationStep {
+	var tokenCache *auth.TokenCache
+	if useTokenCache && accessToken == "" {
+		tokenCache = newTokenCache(ctx)
+	}
+	// Tokens are cached per IAM endpoint, the one issuing them, which the
+	// `iam` endpoint override may point away from host.
+	tokenIssuer := endpoints["iam"]
+
+	// Logging in and reading the account information is deferred to the
+	// first API call.
+	clientOptions := client.Options{
+		Region:     region,
+		Regions:    regions,
+		HTTPClient: configuration.HTTPClient,
+		Limiter:    limiter,
+	}
+	providerClient := client.NewClient(clientOptions, func(ctx context.Context) (*client.Config, diag.Diagnostics) {
+		cached := false
+		if accessToken != "" {
+			// A static access token skips the login and MFA flow, it is
+			// validated below when the account information is fetched.
+			configuration.AccessToken = accessToken
+		} else {
+			if tokenCache != nil {
+				configuration.AccessToken, cached = tokenCache.Get(tokenIssuer, domainId, username)
+			}
+			if !cached {
+				token, diags := login(ctx, loginAPIClient, credentials)
+				if diags.HasError() {
+					return nil, diags
+				}
+				configuration.AccessToken = token
+				putCachedToken(ctx, tokenCache, tokenIssuer, domainId, username, token)
+			}
+		}
 
-		if mfaCode == "" {
-			resp.Diagnostics.AddAttributeError(
-				path.Root("mfa_code"),
-				"Missing Viettelidc API MfaCode",
-				"The provider cannot create the Viettelidc API client as there is a missing or empty value for the Viettelidc API mfaCode. "+
-					"Set the password value in the configuration or use the VIETTELIDC_MFA_CODE environment variable. "+
-					"If either is already set, ensure the value is not empty.",
+		// Every API client shares the HTTP client, and so a single token source.
+		// When a request is rejected with 401 Unauthorized, e.g. because the
+		// token expired during a long running wait or a cached token has been
+		// revoked, the provider logs in again and retries the request once.
+		var relogin auth.LoginFunc
+		if accessToken == "" {
+			relogin = func(ctx context.Context) (string, error) {
+				// The API rejected the cached token, which must not be
+				// reused even if logging in again fails.
+				if cached {
+					deleteCachedToken(ctx, tokenCache, tokenIssuer, domainId, username)
+					cached = false
+				}
+				token, err := auth.Login(ctx, loginAPIClient, credentials)
+				if err != nil {
+					return "", err
+				}
+				putCachedToken(ctx, tokenCache, tokenIssuer, domainId, username, token)
+				return token, nil
+			}
+		}
+		configuration.HTTPClient.Transport = auth.NewTransport(retryTransport, auth.NewTokenSource(configuration.AccessToken, relogin))
+
+		var diags diag.Diagnostics
+		accountRes, _, err := iam.NewAPIClient(configuration).AccountClientApi.GetAccountInfoClient(ctx)
+		if err != nil && accessToken != "" {
+			diags.AddError(
+				"Invalid Viettelidc API Access Token",
+				"The provider cannot create the Viettelidc API client as the Viettelidc API rejected the access token. "+
+					"Ensure the token set in the configuration or in the VIETTELIDC_ACCESS_TOKEN environment variable has not expired.\n\n"+
+					"Viettelidc Client Error: "+err.Error(),
 			)
-			return
+			return nil, diags
 		}
-
-		exchangeTokenRes, _, err := iamAPIClient.AuthorizationControllerApi.VerifyMfaTokenCode(ctx, iam.LoginViaPageWithMfaCodeRequest{
-			MfaToken: loginRes.Data,
-			MfaCode:  mfaCode,
-		})
 		if err != nil {
-			resp.Diagnostics.AddError(
+			diags.AddError(
 				"Unable to Create Viettelidc API Client",
 				"An unexpected error occurred when creating the Viettelidc API client. "+
 					"If the error is not clear, please contact the provider developers.\n\n"+
 					"Viettelidc Client Error: "+err.Error(),
 			)
-			return
+			return nil, diags
+		}
+
+		account := client.Account{
+			Id:         accountRes.Data.Id,
+			DomainId:   accountRes.Data.DomainId,
+			CustomerId: accountRes.Data.CustomerId,
+		}
+		configuration.Id = account.Id
+		configuration.DomainId = account.DomainId
+		configuration.CustomerId = account.CustomerId
+
+		// Every service gets its own base path, all of them share the HTTP
+		// client, and so the access token, and the account information.
+		serviceConfiguration := func(endpoint string) *viettelidc.Configuration {
+			serviceConfiguration := *configuration
+			serviceConfiguration.BasePath = endpoint
+			return &serviceConfiguration
+		}
+
+		return &client.Config{
+			Iam:     configuration,
+			Voks:    serviceConfiguration(endpoints["voks"]),
+			Vpc:     serviceConfiguration(endpoints["vpc"]),
+			Account: account,
+			Region:  region,
+			Regions: regions,
+		}, diags
+	})
+
+	// Make the Viettelidc client available during DataSource and Resource
+	// type Configure methods.
+	resp.DataSourceData = providerClient
+	resp.ResourceData = providerClient
+}
+
+// values returns the endpoint overrides keyed by service name.
+func (e *endpointsModel) values() map[string]types.String {
+	return map[string]types.String{
+		"iam":  e.Iam,
+		"voks": e.Voks,
+		"vpc":  e.Vpc,
+	}
+}
+
+// regions returns the regions declared in the configuration, sorted by
+// code.
+func (m *viettelidcProviderModel) regions(ctx context.Context) ([]client.Region, diag.Diagnostics) {
+	var models map[string]regionModel
+	diags := m.Regions.ElementsAs(ctx, &models, false)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	regions := make([]client.Region, 0, len(models))
+	for code, model := range models {
+		if err := validateEndpoint(model.Endpoint.ValueString()); err != nil {
+			diags.AddAttributeError(
+				path.Root("regions").AtMapKey(code).AtName("endpoint"),
+				"Invalid Viettelidc Region Endpoint",
+				fmt.Sprintf("The provider cannot create the Viettelidc API client as the API gateway of the region %q is not a valid URL.\n\n", code)+
+					"Error: "+err.Error(),
+			)
+		}
+
+		region := client.Region{
+			Code:     code,
+			Name:     model.Name.ValueString(),
+			Endpoint: model.Endpoint.ValueString(),
+			HostId:   model.HostId.ValueInt32Pointer(),
+		}
+		for zone, name := range model.Zones {
+			region.Zones = append(region.Zones, client.AvailabilityZone{Code: zone, Name: name})
+		}
+		sort.Slice(region.Zones, func(i, j int) bool {
+			return region.Zones[i].Code < region.Zones[j].Code
+		})
+		regions = append(regions, region)
+	}
+	sort.Slice(regions, func(i, j int) bool {
+		return regions[i].Code < regions[j].Code
+	})
+	return regions, diags
+}
+
+// unknownAttributes returns the names of the attributes whose value is
+// unknown, in whole or in part.
+func (m *viettelidcProviderModel) unknownAttributes(ctx context.Context) []string {
+	values := map[string]attr.Value{
+		"host":                    m.Host,
+		"region":                  m.Region,
+		"regions":                 m.Regions,
+		"domain_id":               m.DomainId,
+		"username":                m.Username,
+		"password":                m.Password,
+		"mfa_code":                m.MfaCode,
+		"mfa_totp_secret":         m.MfaTotpSecret,
+		"access_token":            m.AccessToken,
+		"profile":                 m.Profile,
+		"shared_credentials_file": m.SharedCredentialsFile,
+		"token_cache":             m.TokenCache,
+		"ca_bundle":               m.CaBundle,
+		"insecure":                m.Insecure,
+		"http_proxy":              m.HttpProxy,
+		"client_certificate":      m.ClientCertificate,
+		"client_key":              m.ClientKey,
+		"max_retries":             m.MaxRetries,
+		"retry_max_wait":          m.RetryMaxWait,
+		"max_requests_per_second": m.MaxRequestsPerSecond,
+		"max_concurrent_requests": m.MaxConcurrentRequests,
+	}
+	if m.Endpoints != nil {
+		for name, endpoint := range m.Endpoints.values() {
+			values["endpoints."+name] = endpoint
+		}
+	}
+
+	var unknown []string
+	for name, value := range values {
+		if value, err := value.ToTerraformValue(ctx); err != nil || !value.IsFullyKnown() {
+			unknown = append(unknown, name)
 		}
-		configuration.AccessToken = exchangeTokenRes.Data
-	} else {
-		configuration.AccessToken = loginRes.Data
 	}
+	sort.Strings(unknown)
+	return unknown
+}
 
-	accountRes, _, err := iamAPIClient.AccountClientApi.GetAccountInfoClient(ctx)
+// validateEndpoint ensures the endpoint is an absolute http or https URL.
+func validateEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("expected an absolute http or https URL, got %q", endpoint)
+	}
+	return nil
+}
+
+// loadProfile reads the selected profile of the shared credentials file. A
+// missing file or default profile is not an error unless the profile has been
+// explicitly selected, in which case an empty profile is returned.
+func loadProfile(config viettelidcProviderModel) (*auth.Profile, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	name := os.Getenv("VIETTELIDC_PROFILE")
+	if !config.Profile.IsNull() {
+		name = config.Profile.ValueString()
+	}
+
+	filename := os.Getenv("VIETTELIDC_SHARED_CREDENTIALS_FILE")
+	if !config.SharedCredentialsFile.IsNull() {
+		filename = config.SharedCredentialsFile.ValueString()
+	}
+
+	explicit := name != ""
+	if !explicit {
+		name = auth.DefaultProfile
+	}
+
+	if filename == "" {
+		defaultFilename, err := auth.DefaultCredentialsFile()
+		if err != nil {
+			if explicit {
+				diags.AddAttributeError(
+					path.Root("shared_credentials_file"),
+					"Unable to Locate Viettelidc Shared Credentials File",
+					"The provider cannot determine the default location of the shared credentials file. "+
+						"Set the shared_credentials_file value in the configuration or use the VIETTELIDC_SHARED_CREDENTIALS_FILE environment variable.\n\n"+
+						"Error: "+err.Error(),
+				)
+			}
+			return &auth.Profile{}, diags
+		}
+		filename = defaultFilename
+	}
+
+	profile, err := auth.LoadProfile(filename, name)
+	if err != nil {
+		if !explicit && (auth.IsNotExist(err) || errors.Is(err, auth.ErrProfileNotFound)) {
+			return &auth.Profile{}, diags
+		}
+		diags.AddAttributeError(
+			path.Root("profile"),
+			"Unable to Load Viettelidc Profile",
+			fmt.Sprintf("The provider cannot read the profile %q from the shared credentials file %s. ", name, filename)+
+				"Ensure the file exists and contains a section for the profile, or unset the profile value in the configuration and the VIETTELIDC_PROFILE environment variable.\n\n"+
+				"Error: "+err.Error(),
+		)
+		return nil, diags
+	}
+
+	return profile, diags
+}
+
+// newTokenCache returns the on-disk access token cache, or nil when its
+// location cannot be determined.
+func newTokenCache(ctx context.Context) *auth.TokenCache {
+	dir := os.Getenv("VIETTELIDC_TOKEN_CACHE_DIR")
+	if dir == "" {
+		defaultDir, err := auth.DefaultTokenCacheDir()
+		if err != nil {
+			tflog.Warn(ctx, "Unable to locate Viettelidc API token cache, caching is disabled", map[string]any{"error": err.Error()})
+			return nil
+		}
+		dir = defaultDir
+	}
+	return auth.NewTokenCache(dir)
+}
+
+// putCachedToken stores the access token in the token cache, if enabled. A
+// failure only costs a login in the next provider process, so it is logged
+// rather than reported.
+func putCachedToken(ctx context.Context, tokenCache *auth.TokenCache, host, domainId, username, token string) {
+	if tokenCache == nil {
+		return
+	}
+	if err := tokenCache.Put(host, domainId, username, token); err != nil {
+		tflog.Warn(ctx, "Unable to write Viettelidc API token cache", map[string]any{"error": err.Error()})
+	}
+}
+
+// deleteCachedToken removes the cached access token, logging failures as the
+// cache is only an optimization.
+func deleteCachedToken(ctx context.Context, tokenCache *auth.TokenCache, host, domainId, username string) {
+	if tokenCache == nil {
+		return
+	}
+	if err := tokenCache.Delete(host, domainId, username); err != nil {
+		tflog.Warn(ctx, "Unable to delete Viettelidc API token cache", map[string]any{"error": err.Error()})
+	}
+}
+
+// getEnv returns the value of the environment variable named by the key, or
+// fallback when the variable is not set or empty.
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getEnvInt64 returns the value of the environment variable matching the
+// attribute as a number, or fallback when the variable is not set.
+func getEnvInt64(attribute string, fallback int64) (int64, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	key := "VIETTELIDC_" + strings.ToUpper(attribute)
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, diags
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root(attribute),
+			"Invalid Viettelidc Environment Variable",
+			fmt.Sprintf("The provider cannot create the Viettelidc API client as the %s environment variable is not an integer.\n\n", key)+
+				"Error: "+err.Error(),
+		)
+		return fallback, diags
+	}
+	return parsed, diags
+}
+
+// getEnvBool returns the value of the environment variable matching the
+// attribute as a boolean, or fallback when the variable is not set.
+func getEnvBool(attribute string, fallback bool) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	key := "VIETTELIDC_" + strings.ToUpper(attribute)
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, diags
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root(attribute),
+			"Invalid Viettelidc Environment Variable",
+			fmt.Sprintf("The provider cannot create the Viettelidc API client as the %s environment variable is not a boolean.\n\n", key)+
+				"Error: "+err.Error(),
+		)
+		return fallback, diags
+	}
+	return parsed, diags
+}
+
+// getEnvFloat64 returns the value of the environment variable matching the
+// attribute as a number, or fallback when the variable is not set.
+func getEnvFloat64(attribute string, fallback float64) (float64, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	key := "VIETTELIDC_" + strings.ToUpper(attribute)
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, diags
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root(attribute),
+			"Invalid Viettelidc Environment Variable",
+			fmt.Sprintf("The provider cannot create the Viettelidc API client as the %s environment variable is not a number.\n\n", key)+
+				"Error: "+err.Error(),
+		)
+		return fallback, diags
+	}
+	return parsed, diags
+}
+
+// login exchanges the credentials for an access token, translating failures
+// into diagnostics. It runs on the first API call of a resource or data
+// source, so the diagnostics do not point at provider attributes.
+func login(ctx context.Context, loginAPIClient *iam.APIClient, credentials auth.Credentials) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	token, err := auth.Login(ctx, loginAPIClient, credentials)
+	switch {
+	case errors.Is(err, auth.ErrInvalidTOTPSecret):
+		diags.AddError(
+			"Invalid Viettelidc API MFA TOTP Secret",
+			"The provider cannot generate the Viettelidc API MFA code from the configured TOTP secret. "+
+				"Set the base32 secret shown in the ViettelIdc portal in the configuration or use the VIETTELIDC_MFA_TOTP_SECRET environment variable.\n\n"+
+				"Error: "+err.Error(),
+		)
+	case errors.Is(err, auth.ErrMfaCodeRequired):
+		diags.AddError(
+			"Missing Viettelidc API MfaCode",
+			"The provider cannot create the Viettelidc API client as there is a missing or empty value for the Viettelidc API mfaCode. "+
+				"Set the password value in the configuration or use the VIETTELIDC_MFA_CODE environment variable, or set a TOTP secret instead. "+
+				"If either is already set, ensure the value is not empty.",
+		)
+	case err != nil:
+		diags.AddError(
 			"Unable to Create Viettelidc API Client",
 			"An unexpected error occurred when creating the Viettelidc API client. "+
 				"If the error is not clear, please contact the provider developers.\n\n"+
 				"Viettelidc Client Error: "+err.Error(),
 		)
-		return
 	}
 
-	configuration.Id = accountRes.Data.Id
-	configuration.DomainId = accountRes.Data.DomainId
-	configuration.CustomerId = accountRes.Data.CustomerId
-
-	//// Make the Viettelidc client available during DataSource and Resource
-	//// type Configure methods.
-	resp.DataSourceData = configuration
-	resp.ResourceData = configuration
+	return token, diags
 }
 
 // DataSources defines the data sources implemented in the provider.
 func (p *viettelidcProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
+		regionDatasource.NewRegionsDatasource,
+		regionDatasource.NewAvailabilityZonesDatasource,
 		vpcDatasource.NewVpcsDatasource,
 		vpcDatasource.NewVpcDatasource,
 		vpcDatasource.NewVpcQuotaLimitDatasource,
@@ -0,0 +1,135 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package datasource
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-viettelidc/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &availabilityZonesDatasource{}
+	_ datasource.DataSourceWithConfigure = &availabilityZonesDatasource{}
+)
+
+type availabilityZonesDatasource struct {
+	client client.ProviderData
+}
+
+type AvailabilityZonesDatasourceModel struct {
+	Region types.String            `tfsdk:"region"`
+	Items  []AvailabilityZoneModel `tfsdk:"items"`
+}
+
+type AvailabilityZoneModel struct {
+	Code types.String `tfsdk:"code"`
+	Name types.String `tfsdk:"name"`
+}
+
+func NewAvailabilityZonesDatasource() datasource.DataSource {
+	return &availabilityZonesDatasource{}
+}
+
+func (a *availabilityZonesDatasource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if request.ProviderData == nil {
+		return
+	}
+
+	providerClient, ok := request.ProviderData.(client.ProviderData)
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected client.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	a.client = providerClient
+}
+
+func (a *availabilityZonesDatasource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_availability_zones"
+}
+
+func (a *availabilityZonesDatasource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Description: "Retrieve list of availability zones of a ViettelIdc region.",
+		Attributes: map[string]schema.Attribute{
+			"region": schema.StringAttribute{
+				Description: "Region of the availability zones. Defaults to the region of the provider.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"items": schema.ListNestedAttribute{
+				Description: "List of availability zones.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"code": schema.StringAttribute{
+							Description: "Code of the availability zone.",
+							Computed:    true,
+						},
+						"name": schema.StringAttribute{
+							Description: "Name of the data center of the availability zone.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (a *availabilityZonesDatasource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	if a.client.DeferDataSourceRead(request, response) {
+		return
+	}
+
+	var data AvailabilityZonesDatasourceModel
+	diags := request.Config.Get(ctx, &data)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	code := data.Region.ValueString()
+	if code == "" {
+		code = a.client.Region()
+	}
+	if code == "" {
+		response.Diagnostics.AddAttributeError(
+			path.Root("region"),
+			"Missing Region",
+			"Set `region` on the data source or the provider to list its availability zones.",
+		)
+		return
+	}
+
+	region, err := client.LookupRegion(a.client.Regions(), code)
+	if err != nil {
+		response.Diagnostics.AddAttributeError(path.Root("region"), "Invalid Viettelidc Region", err.Error())
+		return
+	}
+
+	data.Region = types.StringValue(region.Code)
+	data.Items = nil
+	for _, zone := range region.Zones {
+		data.Items = append(data.Items, AvailabilityZoneModel{
+			Code: types.StringValue(zone.Code),
+			Name: types.StringValue(zone.Name),
+		})
+	}
+
+	diags = response.State.Set(ctx, &data)
+	response.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package datasource
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-viettelidc/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &regionsDatasource{}
+	_ datasource.DataSourceWithConfigure = &regionsDatasource{}
+)
+
+type regionsDatasource struct {
+	client client.ProviderData
+}
+
+type RegionsDatasourceModel struct {
+	Current types.String  `tfsdk:"current"`
+	Items   []RegionModel `tfsdk:"items"`
+}
+
+type RegionModel struct {
+	Code     types.String `tfsdk:"code"`
+	Name     types.String `tfsdk:"name"`
+	Endpoint types.String `tfsdk:"endpoint"`
+	HostId   types.Int32  `tfsdk:"host_id"`
+}
+
+func NewRegionsDatasource() datasource.DataSource {
+	return &regionsDatasource{}
+}
+
+func (r *regionsDatasource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if request.ProviderData == nil {
+		return
+	}
+
+	providerClient, ok := request.ProviderData.(client.ProviderData)
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected client.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = providerClient
+}
+
+func (r *regionsDatasource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_regions"
+}
+
+func (r *regionsDatasource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Description: "Retrieve list of ViettelIdc regions declared in the `regions` of the provider.",
+		Attributes: map[string]schema.Attribute{
+			"current": schema.StringAttribute{
+				Description: "Region of the provider, empty when it is not set.",
+				Computed:    true,
+			},
+			"items": schema.ListNestedAttribute{
+				Description: "List of regions.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"code": schema.StringAttribute{
+							Description: "Code of the region, as set in the `region` arguments.",
+							Computed:    true,
+						},
+						"name": schema.StringAttribute{
+							Description: "Name of the region.",
+							Computed:    true,
+						},
+						"endpoint": schema.StringAttribute{
+							Description: "Base URL of the API gateway of the region.",
+							Computed:    true,
+						},
+						"host_id": schema.Int32Attribute{
+							Description: "Host ID of the region in the VPC API, null when it is not declared.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *regionsDatasource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	if r.client.DeferDataSourceRead(request, response) {
+		return
+	}
+
+	var state RegionsDatasourceModel
+	state.Current = types.StringValue(r.client.Region())
+	for _, region := range r.client.Regions() {
+		state.Items = append(state.Items, RegionModel{
+			Code:     types.StringValue(region.Code),
+			Name:     types.StringValue(region.Name),
+			Endpoint: types.StringValue(region.Endpoint),
+			HostId:   types.Int32PointerValue(region.HostId),
+		})
+	}
+
+	diags := response.State.Set(ctx, &state)
+	response.Diagnostics.Append(diags...)
+}
@@ -9,8 +9,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/viettelidc-provider/viettelidc-api-client-go/service/voks"
-	"github.com/viettelidc-provider/viettelidc-api-client-go/viettelidc"
+	"terraform-provider-viettelidc/internal/client"
 )
 
 var (
@@ -19,7 +18,7 @@ var (
 )
 
 type addonDatasource struct {
-	client *voks.APIClient
+	client client.ProviderData
 }
 
 type AddonDataSourceModel struct {
@@ -27,6 +26,7 @@ type AddonDataSourceModel struct {
 	Name      types.String `tfsdk:"name"`
 	Version   types.String `tfsdk:"version"`
 	Status    types.String `tfsdk:"status"`
+	Region    types.String `tfsdk:"region"`
 }
 
 func NewAddonDataSource() datasource.DataSource {
@@ -40,17 +40,17 @@ func (a *addonDatasource) Configure(ctx context.Context, request datasource.Conf
 		return
 	}
 
-	cfg, ok := request.ProviderData.(*viettelidc.Configuration)
+	providerClient, ok := request.ProviderData.(client.ProviderData)
 	if !ok {
 		response.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *apiclient.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected client.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 
 		return
 	}
 
-	a.client = voks.NewAPIClient(*cfg)
+	a.client = providerClient
 }
 
 func (a *addonDatasource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
@@ -77,11 +77,19 @@ func (a *addonDatasource) Schema(ctx context.Context, request datasource.SchemaR
 				Description: "The current status of Add-on. Valid values: `ACTIVE`, `INACTIVE`, `INSTALLING`, `UNINSTALLING`.",
 				Computed:    true,
 			},
+			"region": schema.StringAttribute{
+				Description: "Region to read from. Defaults to the region of the provider.",
+				Optional:    true,
+			},
 		},
 	}
 }
 
 func (a *addonDatasource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	if a.client.DeferDataSourceRead(request, response) {
+		return
+	}
+
 	var data AddonDataSourceModel
 	diags := request.Config.Get(ctx, &data)
 	response.Diagnostics.Append(diags...)
@@ -89,11 +97,18 @@ func (a *addonDatasource) Read(ctx context.Context, request datasource.ReadReque
 		return
 	}
 
-	addonRes, _, err := a.client.AddOnApi.GetDetailAddon(ctx, data.ClusterId.ValueInt32(), data.Name.ValueString())
+	apiClient, diags := a.client.Voks(ctx, data.Region.ValueString())
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	addonRes, httpRes, err := apiClient.AddOnApi.GetDetailAddon(ctx, data.ClusterId.ValueInt32(), data.Name.ValueString())
 	if err != nil {
-		response.Diagnostics.AddError(
+		response.Diagnostics.Append(client.ErrorDiagnostics(
 			"Error reading Cluster Addon detail",
-			"Could not read Cluster Addon detail, unexpected error: "+err.Error())
+			"Could not read Cluster Addon detail",
+			httpRes, err, nil)...)
 		return
 	}
 
@@ -11,7 +11,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/viettelidc-provider/viettelidc-api-client-go/service/voks"
-	"github.com/viettelidc-provider/viettelidc-api-client-go/viettelidc"
+	"terraform-provider-viettelidc/internal/client"
 )
 
 var (
@@ -20,7 +20,7 @@ var (
 )
 
 type addonVersionsDatasource struct {
-	client *voks.APIClient
+	client client.ProviderData
 }
 
 type AddonVersionsDataSourceModel struct {
@@ -28,6 +28,7 @@ type AddonVersionsDataSourceModel struct {
 	KubernetesVersion types.String         `tfsdk:"kubernetes_version"`
 	Filter            *AddOnVersionsFilter `tfsdk:"filter"`
 	Versions          types.List           `tfsdk:"versions"`
+	Region            types.String         `tfsdk:"region"`
 }
 
 type AddOnVersionsFilter struct {
@@ -45,17 +46,17 @@ func (a *addonVersionsDatasource) Configure(ctx context.Context, request datasou
 		return
 	}
 
-	cfg, ok := request.ProviderData.(*viettelidc.Configuration)
+	providerClient, ok := request.ProviderData.(client.ProviderData)
 	if !ok {
 		response.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *apiclient.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected client.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 
 		return
 	}
 
-	a.client = voks.NewAPIClient(*cfg)
+	a.client = providerClient
 }
 
 func (a *addonVersionsDatasource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
@@ -90,11 +91,18 @@ func (a *addonVersionsDatasource) Schema(ctx context.Context, request datasource
 				Computed:    true,
 				ElementType: types.StringType,
 			},
+			"region": schema.StringAttribute{
+				Description: "Region to read from. Defaults to the region of the provider.",
+				Optional:    true,
+			},
 		},
 	}
 }
 
 func (a *addonVersionsDatasource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	if a.client.DeferDataSourceRead(request, response) {
+		return
+	}
 
 	var data AddonVersionsDataSourceModel
 	diags := request.Config.Get(ctx, &data)
@@ -103,15 +111,23 @@ func (a *addonVersionsDatasource) Read(ctx context.Context, request datasource.R
 		return
 	}
 
+	apiClient, diags := a.client.Voks(ctx, data.Region.ValueString())
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
 	opts := &voks.AddOnApiGetAllAddonVersionOpts{}
 	if data.Filter != nil {
 		opts.Version = optional.NewString(data.Filter.Version.ValueString())
 	}
 
-	addonVersions, _, err := a.client.AddOnApi.GetAllAddonVersion(ctx, data.Name.ValueString(), data.KubernetesVersion.ValueString(), opts)
+	addonVersions, httpRes, err := apiClient.AddOnApi.GetAllAddonVersion(ctx, data.Name.ValueString(), data.KubernetesVersion.ValueString(), opts)
 	if err != nil {
-		response.Diagnostics.AddError("Error reading Addon Versions",
-			"Could not read Addon Versions, unexpected error: "+err.Error())
+		response.Diagnostics.Append(client.ErrorDiagnostics(
+			"Error reading Addon Versions",
+			"Could not read Addon Versions",
+			httpRes, err, nil)...)
 		return
 	}
 
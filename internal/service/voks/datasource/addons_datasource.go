@@ -11,7 +11,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/viettelidc-provider/viettelidc-api-client-go/service/voks"
-	"github.com/viettelidc-provider/viettelidc-api-client-go/viettelidc"
+	"terraform-provider-viettelidc/internal/client"
 )
 
 var (
@@ -20,13 +20,14 @@ var (
 )
 
 type addonsDatasource struct {
-	client *voks.APIClient
+	client client.ProviderData
 }
 
 type AddonsDataSourceModel struct {
 	KubernetesVersion types.String `tfsdk:"kubernetes_version"`
 	Names             types.List   `tfsdk:"names"`
 	Filter            *Filter      `tfsdk:"filter"`
+	Region            types.String `tfsdk:"region"`
 }
 
 type Filter struct {
@@ -44,17 +45,17 @@ func (a *addonsDatasource) Configure(ctx context.Context, request datasource.Con
 		return
 	}
 
-	cfg, ok := request.ProviderData.(*viettelidc.Configuration)
+	providerClient, ok := request.ProviderData.(client.ProviderData)
 	if !ok {
 		response.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *apiclient.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected client.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 
 		return
 	}
 
-	a.client = voks.NewAPIClient(*cfg)
+	a.client = providerClient
 }
 
 func (a *addonsDatasource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
@@ -85,11 +86,18 @@ func (a *addonsDatasource) Schema(ctx context.Context, request datasource.Schema
 				Computed:    true,
 				ElementType: types.StringType,
 			},
+			"region": schema.StringAttribute{
+				Description: "Region to read from. Defaults to the region of the provider.",
+				Optional:    true,
+			},
 		},
 	}
 }
 
 func (a *addonsDatasource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	if a.client.DeferDataSourceRead(request, response) {
+		return
+	}
 
 	var data AddonsDataSourceModel
 	diags := request.Config.Get(ctx, &data)
@@ -98,18 +106,25 @@ func (a *addonsDatasource) Read(ctx context.Context, request datasource.ReadRequ
 		return
 	}
 
+	apiClient, diags := a.client.Voks(ctx, data.Region.ValueString())
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
 	opts := &voks.AddOnApiGetAllAddOnOpts{}
 	if data.Filter != nil {
 		opts.Name = optional.NewString(data.Filter.Name.ValueString())
 	}
 
 	var names []types.String
-	addons, _, err := a.client.AddOnApi.GetAllAddOn(ctx, data.KubernetesVersion.ValueString(), opts)
+	addons, httpRes, err := apiClient.AddOnApi.GetAllAddOn(ctx, data.KubernetesVersion.ValueString(), opts)
 
 	if err != nil {
-		response.Diagnostics.AddError(
+		response.Diagnostics.Append(client.ErrorDiagnostics(
 			"Error reading Addons",
-			"Could not read Addons, unexpected error: "+err.Error())
+			"Could not read Addons",
+			httpRes, err, nil)...)
 		return
 	}
 
@@ -10,11 +10,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/viettelidc-provider/viettelidc-api-client-go/service/voks"
-	"github.com/viettelidc-provider/viettelidc-api-client-go/viettelidc"
+	"terraform-provider-viettelidc/internal/client"
 )
 
 type clusterDatasource struct {
-	client *voks.APIClient
+	client client.ProviderData
 }
 
 type ClusterDataSourceModel struct {
@@ -25,6 +25,7 @@ type ClusterDataSourceModel struct {
 	Endpoint  types.String    `tfsdk:"endpoint"`
 	Nfs       *NfsBlock       `tfsdk:"nfs"`
 	VpcConfig *VpcConfigBlock `tfsdk:"vpc_config"`
+	Region    types.String    `tfsdk:"region"`
 }
 
 type VpcConfigBlock struct {
@@ -57,17 +58,17 @@ func (c *clusterDatasource) Configure(ctx context.Context, request datasource.Co
 		return
 	}
 
-	cfg, ok := request.ProviderData.(*viettelidc.Configuration)
+	providerClient, ok := request.ProviderData.(client.ProviderData)
 	if !ok {
 		response.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *apiclient.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected client.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 
 		return
 	}
 
-	c.client = voks.NewAPIClient(*cfg)
+	c.client = providerClient
 }
 
 func (c *clusterDatasource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
@@ -125,6 +126,10 @@ func (c *clusterDatasource) Schema(ctx context.Context, request datasource.Schem
 					},
 				},
 			},
+			"region": schema.StringAttribute{
+				Description: "Region to read from. Defaults to the region of the provider.",
+				Optional:    true,
+			},
 		},
 		Blocks: map[string]schema.Block{
 			"vpc_config": schema.SingleNestedBlock{
@@ -151,6 +156,9 @@ func (c *clusterDatasource) Schema(ctx context.Context, request datasource.Schem
 }
 
 func (c *clusterDatasource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	if c.client.DeferDataSourceRead(request, response) {
+		return
+	}
 
 	var data ClusterDataSourceModel
 	diags := request.Config.Get(ctx, &data)
@@ -159,11 +167,18 @@ func (c *clusterDatasource) Read(ctx context.Context, request datasource.ReadReq
 		return
 	}
 
-	cluster, _, err := c.client.ClusterApi.DetailCluster(ctx, data.ID.ValueInt32())
+	apiClient, diags := c.client.Voks(ctx, data.Region.ValueString())
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	cluster, httpRes, err := apiClient.ClusterApi.DetailCluster(ctx, data.ID.ValueInt32())
 	if err != nil {
-		response.Diagnostics.AddError(
+		response.Diagnostics.Append(client.ErrorDiagnostics(
 			"Error reading Cluster detail",
-			"Could not read Cluster detail, unexpected error: "+err.Error())
+			"Could not read Cluster detail",
+			httpRes, err, nil)...)
 		return
 	}
 
@@ -191,13 +206,14 @@ func (c *clusterDatasource) Read(ctx context.Context, request datasource.ReadReq
 		data.VpcConfig.SubnetIds = subnetIds
 	}
 
-	nfs, _, err := c.client.NFSApi.DetailNfsStorage(ctx, voks.BaseResourceReq{
+	nfs, httpRes, err := apiClient.NFSApi.DetailNfsStorage(client.WithRetrySafe(ctx), voks.BaseResourceReq{
 		ClusterId: cluster.Id,
 	})
 	if err != nil {
-		response.Diagnostics.AddError(
+		response.Diagnostics.Append(client.ErrorDiagnostics(
 			"Error reading Cluster NFS detail",
-			"Could not read Cluster NFS detail, unexpected error: "+err.Error())
+			"Could not read Cluster NFS detail",
+			httpRes, err, nil)...)
 		return
 	}
 
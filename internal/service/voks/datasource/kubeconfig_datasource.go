@@ -10,7 +10,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/viettelidc-provider/viettelidc-api-client-go/service/voks"
-	"github.com/viettelidc-provider/viettelidc-api-client-go/viettelidc"
+	"terraform-provider-viettelidc/internal/client"
 )
 
 var (
@@ -19,12 +19,13 @@ var (
 )
 
 type kubeconfigDatasource struct {
-	client *voks.APIClient
+	client client.ProviderData
 }
 
 type KubeconfigDataSourceModel struct {
 	ClusterId types.Int32  `tfsdk:"cluster_id"`
 	Value     types.String `tfsdk:"value"`
+	Region    types.String `tfsdk:"region"`
 }
 
 func NewKubeconfigResource() datasource.DataSource {
@@ -38,17 +39,17 @@ func (k *kubeconfigDatasource) Configure(ctx context.Context, request datasource
 		return
 	}
 
-	cfg, ok := request.ProviderData.(*viettelidc.Configuration)
+	providerClient, ok := request.ProviderData.(client.ProviderData)
 	if !ok {
 		response.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *apiclient.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected client.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 
 		return
 	}
 
-	k.client = voks.NewAPIClient(*cfg)
+	k.client = providerClient
 }
 
 func (k *kubeconfigDatasource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
@@ -61,30 +62,43 @@ func (k *kubeconfigDatasource) Schema(ctx context.Context, request datasource.Sc
 		Attributes: map[string]schema.Attribute{
 			"cluster_id": schema.Int32Attribute{
 				Description: "Id of the Cluster.",
-				Required: true,
+				Required:    true,
 			},
 			"value": schema.StringAttribute{
 				Description: "The kubeconfig file is essential for configuring access to the cluster, providing connection details, authentication credentials, and other configurations.",
-				Computed: true,
+				Computed:    true,
+			},
+			"region": schema.StringAttribute{
+				Description: "Region to read from. Defaults to the region of the provider.",
+				Optional:    true,
 			},
 		},
 	}
 }
 
 func (k *kubeconfigDatasource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	if k.client.DeferDataSourceRead(request, response) {
+		return
+	}
 
 	var data KubeconfigDataSourceModel
 	// Read Terraform configuration data into the model
 	response.Diagnostics.Append(request.Config.Get(ctx, &data)...)
 
-	res, _, err := k.client.ClusterApi.KubeConfigCluster(ctx, voks.BaseResourceReq{
+	apiClient, diags := k.client.Voks(ctx, data.Region.ValueString())
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	res, httpRes, err := apiClient.ClusterApi.KubeConfigCluster(client.WithRetrySafe(ctx), voks.BaseResourceReq{
 		ClusterId: data.ClusterId.ValueInt32(),
 	})
 	if err != nil {
-		response.Diagnostics.AddError(
+		response.Diagnostics.Append(client.ErrorDiagnostics(
 			"Unable to Read Kubeconfig Info",
-			err.Error(),
-		)
+			"Could not read Kubeconfig of the Cluster",
+			httpRes, err, nil)...)
 		return
 	}
 
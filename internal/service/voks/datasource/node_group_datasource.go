@@ -9,12 +9,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/viettelidc-provider/viettelidc-api-client-go/service/voks"
-	"github.com/viettelidc-provider/viettelidc-api-client-go/viettelidc"
+	"terraform-provider-viettelidc/internal/client"
 )
 
 type NodeGroupDatasource struct {
-	client *voks.APIClient
+	client client.ProviderData
 }
 
 type NodeGroupDataSourceModel struct {
@@ -27,6 +26,7 @@ type NodeGroupDataSourceModel struct {
 	Labels        map[string]types.String `tfsdk:"labels"`
 	Taint         []TaintConfigBlock      `tfsdk:"taint"`
 	Status        types.String            `tfsdk:"status"`
+	Region        types.String            `tfsdk:"region"`
 }
 
 type ScalingConfigBlock struct {
@@ -57,17 +57,17 @@ func (n *NodeGroupDatasource) Configure(ctx context.Context, request datasource.
 		return
 	}
 
-	cfg, ok := request.ProviderData.(*viettelidc.Configuration)
+	providerClient, ok := request.ProviderData.(client.ProviderData)
 	if !ok {
 		response.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *apiclient.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected client.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 
 		return
 	}
 
-	n.client = voks.NewAPIClient(*cfg)
+	n.client = providerClient
 }
 
 func (n *NodeGroupDatasource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
@@ -107,6 +107,10 @@ func (n *NodeGroupDatasource) Schema(ctx context.Context, request datasource.Sch
 				Description: "The current status of Node Group. Valid values: `CREATING`, `UPDATING`, `SUCCESS`, `ERROR`.",
 				Computed:    true,
 			},
+			"region": schema.StringAttribute{
+				Description: "Region to read from. Defaults to the region of the provider.",
+				Optional:    true,
+			},
 		},
 		Blocks: map[string]schema.Block{
 			"scaling_config": schema.SingleNestedBlock{
@@ -150,6 +154,9 @@ func (n *NodeGroupDatasource) Schema(ctx context.Context, request datasource.Sch
 }
 
 func (n *NodeGroupDatasource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	if n.client.DeferDataSourceRead(request, response) {
+		return
+	}
 
 	var data NodeGroupDataSourceModel
 	diags := request.Config.Get(ctx, &data)
@@ -158,11 +165,18 @@ func (n *NodeGroupDatasource) Read(ctx context.Context, request datasource.ReadR
 		return
 	}
 
-	detail, _, err := n.client.NodeGroupApi.DetailNodeGroup(ctx, data.ClusterId.ValueInt32(), data.ID.ValueInt32())
+	apiClient, diags := n.client.Voks(ctx, data.Region.ValueString())
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	detail, httpRes, err := apiClient.NodeGroupApi.DetailNodeGroup(ctx, data.ClusterId.ValueInt32(), data.ID.ValueInt32())
 	if err != nil {
-		response.Diagnostics.AddError(
+		response.Diagnostics.Append(client.ErrorDiagnostics(
 			"Error reading Cluster Node Group detail",
-			"Could not read Cluster Addon detail, unexpected error: "+err.Error())
+			"Could not read Cluster Addon detail",
+			httpRes, err, nil)...)
 		return
 	}
 
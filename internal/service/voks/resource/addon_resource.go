@@ -6,6 +6,7 @@ package resource
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -14,9 +15,12 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/viettelidc-provider/viettelidc-api-client-go/service/voks"
-	"github.com/viettelidc-provider/viettelidc-api-client-go/viettelidc"
+	"slices"
 	"strconv"
 	"strings"
+	"terraform-provider-viettelidc/internal/client"
+	"terraform-provider-viettelidc/internal/client/voksapi"
+	"terraform-provider-viettelidc/internal/waiter"
 	"time"
 )
 
@@ -24,17 +28,44 @@ var (
 	_ resource.Resource                = &addonResource{}
 	_ resource.ResourceWithConfigure   = &addonResource{}
 	_ resource.ResourceWithImportState = &addonResource{}
+	_ resource.ResourceWithModifyPlan  = &addonResource{}
 )
 
+const (
+	addonStatusInstalling   = "installing"
+	addonStatusUninstalling = "uninstalling"
+	addonStatusActive       = "active"
+	addonStatusInactive     = "inactive"
+	addonStatusError        = "error"
+	addonStatusFailed       = "failed"
+
+	addonDefaultCreateTimeout = 20 * time.Minute
+	addonDefaultDeleteTimeout = 20 * time.Minute
+
+	// addonWaitDelay leaves the API time to pick up an installation or an
+	// uninstallation before the addon is first read.
+	addonWaitDelay = 5 * time.Second
+)
+
+// addonFieldPaths maps the fields of the addon requests to the attributes they
+// are configured by.
+var addonFieldPaths = client.FieldPaths{
+	"clusterId": path.Root("cluster_id"),
+	"name":      path.Root("name"),
+	"version":   path.Root("version"),
+}
+
 type addonResource struct {
-	client *voks.APIClient
+	client client.ProviderData
 }
 
 type AddonResourceModel struct {
-	ClusterId types.Int32  `tfsdk:"cluster_id"`
-	Name      types.String `tfsdk:"name"`
-	Version   types.String `tfsdk:"version"`
-	Status    types.String `tfsdk:"status"`
+	ClusterId types.Int32    `tfsdk:"cluster_id"`
+	Name      types.String   `tfsdk:"name"`
+	Version   types.String   `tfsdk:"version"`
+	Status    types.String   `tfsdk:"status"`
+	Region    types.String   `tfsdk:"region"`
+	Timeouts  timeouts.Value `tfsdk:"timeouts"`
 }
 
 func NewAddonResource() resource.Resource {
@@ -48,17 +79,17 @@ func (a *addonResource) Configure(ctx context.Context, request resource.Configur
 		return
 	}
 
-	cfg, ok := request.ProviderData.(*viettelidc.Configuration)
+	providerClient, ok := request.ProviderData.(client.ProviderData)
 	if !ok {
 		response.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *apiclient.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected client.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 
 		return
 	}
 
-	a.client = voks.NewAPIClient(*cfg)
+	a.client = providerClient
 }
 
 func (a *addonResource) Metadata(ctx context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
@@ -94,10 +125,31 @@ func (a *addonResource) Schema(ctx context.Context, request resource.SchemaReque
 				Description: "The current status of Add-on. Valid values: `ACTIVE`, `INACTIVE`, `INSTALLING`, `UNINSTALLING`.",
 				Computed:    true,
 			},
+			"region": schema.StringAttribute{
+				Description: "Region of the resource. Defaults to the region of the provider.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create:            true,
+				CreateDescription: "How long to wait for the Add-on to become active. Defaults to `20m`.",
+				Delete:            true,
+				DeleteDescription: "How long to wait for the Add-on to become inactive. Defaults to `20m`.",
+			}),
 		},
 	}
 }
 
+// ModifyPlan defers the change when the provider configuration or the
+// cluster, e.g. created in the same run, is unknown until apply.
+func (a *addonResource) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	a.client.DeferResourcePlan(ctx, request, response, path.Root("cluster_id"))
+}
+
 func (a *addonResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
 
 	var plan AddonResourceModel
@@ -107,48 +159,79 @@ func (a *addonResource) Create(ctx context.Context, request resource.CreateReque
 		return
 	}
 
-	exitingAddon, _, err := a.client.AddOnApi.GetDetailAddon(ctx, plan.ClusterId.ValueInt32(), plan.Name.ValueString())
+	createTimeout, diags := plan.Timeouts.Create(ctx, addonDefaultCreateTimeout)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	apiClient, diags := a.client.Voks(ctx, plan.Region.ValueString())
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	unlock, diags := lockCluster(ctx, a.client, apiClient, plan.Region.ValueString(), plan.ClusterId.ValueInt32(), createTimeout)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	defer unlock()
+
+	exitingAddon, httpRes, err := apiClient.AddOnApi.GetDetailAddon(ctx, plan.ClusterId.ValueInt32(), plan.Name.ValueString())
 	if err != nil {
-		response.Diagnostics.AddError(
+		response.Diagnostics.Append(client.ErrorDiagnostics(
 			"Error validating Cluster Addon status",
-			"Could not validate Cluster Addon status, unexpected error: "+err.Error())
+			"Could not validate Cluster Addon status",
+			httpRes, err, nil)...)
 		return
 	}
 
-	if exitingAddon.Status != "inactive" {
+	if !strings.EqualFold(exitingAddon.Status, addonStatusInactive) {
 		response.Diagnostics.AddError(
 			"Error Cluster Addon already installed",
 			"This Cluster Addon already installed: "+exitingAddon.Name+" is in "+exitingAddon.Status+" status")
 		return
 	}
 
-	_, err = a.client.AddOnApi.InstallAddOn(ctx, voks.AddonInstallRequest{
+	httpRes, err = apiClient.AddOnApi.InstallAddOn(ctx, voks.AddonInstallRequest{
 		ClusterId: plan.ClusterId.ValueInt32(),
 		Name:      plan.Name.ValueString(),
 		Version:   plan.Version.ValueString(),
 	})
 
 	if err != nil {
-		response.Diagnostics.AddError(
+		response.Diagnostics.Append(client.ErrorDiagnostics(
 			"Error installing Cluster Addon",
-			"Could not create Cluster Addon, unexpected error: "+err.Error())
+			"Could not create Cluster Addon",
+			httpRes, err, addonFieldPaths)...)
 		return
 	}
 
-	for {
-		detailRes, _, err := a.client.AddOnApi.GetDetailAddon(ctx, plan.ClusterId.ValueInt32(), plan.Name.ValueString())
-		if err != nil {
-			response.Diagnostics.AddError(
-				"Error updating Cluster Addon status",
-				"Could not update Cluster Addon status, unexpected error: "+err.Error())
-			return
-		}
-		if detailRes.Status == "active" {
-			plan.Status = types.StringValue(detailRes.Status)
-			break
-		}
-		time.Sleep(10 * time.Second)
+	// Save the Addon right away, so that Terraform keeps track of it when the
+	// wait below fails or is interrupted
+	plan.Status = types.StringValue(addonStatusInstalling)
+	response.Diagnostics.Append(response.State.Set(ctx, &plan)...)
+	response.Diagnostics.Append(markCreateInProgress(ctx, response.Private)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	status, err := waitForAddonStatus(ctx, apiClient, plan.ClusterId.ValueInt32(), plan.Name.ValueString(), addonStatusActive, createTimeout)
+	if err != nil {
+		response.Diagnostics.Append(client.ErrorDiagnostics(
+			"Error waiting for Cluster Addon installation",
+			"Could not wait for Cluster Addon to become active",
+			nil, err, nil)...)
+		response.Diagnostics.Append(createFailedDiagnostic("Cluster Addon", fmt.Sprintf("%d/%s", plan.ClusterId.ValueInt32(), plan.Name.ValueString())))
+		return
 	}
+	plan.Status = types.StringValue(status)
+
+	response.Diagnostics.Append(clearCreateInProgress(ctx, response.Private)...)
 
 	// Set state to fully populated data
 	diags = response.State.Set(ctx, &plan)
@@ -159,6 +242,9 @@ func (a *addonResource) Create(ctx context.Context, request resource.CreateReque
 }
 
 func (a *addonResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
+	if a.client.DeferResourceRead(request, response) {
+		return
+	}
 
 	var state AddonResourceModel
 	diags := request.State.Get(ctx, &state)
@@ -167,11 +253,28 @@ func (a *addonResource) Read(ctx context.Context, request resource.ReadRequest,
 		return
 	}
 
-	addonRes, _, err := a.client.AddOnApi.GetDetailAddon(ctx, state.ClusterId.ValueInt32(), state.Name.ValueString())
+	apiClient, diags := a.client.Voks(ctx, state.Region.ValueString())
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	addonRes, httpRes, err := apiClient.AddOnApi.GetDetailAddon(ctx, state.ClusterId.ValueInt32(), state.Name.ValueString())
+	if client.IsNotFound(httpRes, err) {
+		response.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
-		response.Diagnostics.AddError(
+		response.Diagnostics.Append(client.ErrorDiagnostics(
 			"Error reading Cluster Addon detail",
-			"Could not read Cluster Addon detail, unexpected error: "+err.Error())
+			"Could not read Cluster Addon detail",
+			httpRes, err, nil)...)
+		return
+	}
+
+	// An uninstalled Addon is still listed by the API as inactive
+	if strings.EqualFold(addonRes.Status, addonStatusInactive) {
+		response.State.RemoveResource(ctx)
 		return
 	}
 
@@ -179,6 +282,16 @@ func (a *addonResource) Read(ctx context.Context, request resource.ReadRequest,
 	state.Version = types.StringValue(addonRes.Version)
 	state.Status = types.StringValue(addonRes.Status)
 
+	// An Addon saved before it became active is either active by now, or
+	// still tainted and about to be replaced.
+	inProgress, diags := isCreateInProgress(ctx, request.Private)
+	response.Diagnostics.Append(diags...)
+	if inProgress && strings.EqualFold(addonRes.Status, addonStatusActive) {
+		response.Diagnostics.Append(clearCreateInProgress(ctx, response.Private)...)
+	} else if inProgress {
+		response.Diagnostics.Append(createInProgressDiagnostic("Cluster Addon", fmt.Sprintf("%d/%s", state.ClusterId.ValueInt32(), state.Name.ValueString()), addonRes.Status))
+	}
+
 	diags = response.State.Set(ctx, &state)
 	response.Diagnostics.Append(diags...)
 	if response.Diagnostics.HasError() {
@@ -187,6 +300,10 @@ func (a *addonResource) Read(ctx context.Context, request resource.ReadRequest,
 }
 
 func (a *addonResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	if a.client.DeferImportState(request, response) {
+		return
+	}
+
 	idParts := strings.Split(request.ID, ",")
 
 	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
@@ -224,29 +341,77 @@ func (a *addonResource) Delete(ctx context.Context, request resource.DeleteReque
 		return
 	}
 
-	_, err := a.client.AddOnApi.UninstallAddOn(ctx, voks.AddonUninstallRequest{
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, addonDefaultDeleteTimeout)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	apiClient, diags := a.client.Voks(ctx, state.Region.ValueString())
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	unlock, diags := lockClusterForDeletion(ctx, a.client, apiClient, state.Region.ValueString(), state.ClusterId.ValueInt32(), deleteTimeout)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	defer unlock()
+
+	httpRes, err := apiClient.AddOnApi.UninstallAddOn(ctx, voks.AddonUninstallRequest{
 		ClusterId: state.ClusterId.ValueInt32(),
 		Name:      state.Name.ValueString(),
 	})
+	if client.IsNotFound(httpRes, err) {
+		// Already uninstalled outside of Terraform
+		return
+	}
 	if err != nil {
-		response.Diagnostics.AddError(
+		response.Diagnostics.Append(client.ErrorDiagnostics(
 			"Error uninstall Cluster Addon",
-			"Could not uninstall Cluster Addon, unexpected error: "+err.Error())
+			"Could not uninstall Cluster Addon",
+			httpRes, err, nil)...)
+		return
+	}
+
+	_, err = waitForAddonStatus(ctx, apiClient, state.ClusterId.ValueInt32(), state.Name.ValueString(), addonStatusInactive, deleteTimeout)
+	if err != nil {
+		response.Diagnostics.Append(client.ErrorDiagnostics(
+			"Error waiting for Cluster Addon uninstallation",
+			"Could not wait for Cluster Addon to become inactive",
+			nil, err, nil)...)
 		return
 	}
+}
 
-	for {
-		detailRes, _, err := a.client.AddOnApi.GetDetailAddon(ctx, state.ClusterId.ValueInt32(), state.Name.ValueString())
-		if err != nil {
-			response.Diagnostics.AddError(
-				"Error updating Cluster Addon status",
-				"Could not update Cluster Addon status, unexpected error: "+err.Error())
-			return
-		}
-		if detailRes.Status == "inactive" {
-			state.Status = types.StringValue(detailRes.Status)
-			break
-		}
-		time.Sleep(10 * time.Second)
+// waitForAddonStatus polls the addon until it reaches the target status or
+// the timeout expires. The addon may still be reported in the opposite status
+// until the API starts installing or uninstalling it.
+func waitForAddonStatus(ctx context.Context, apiClient *voksapi.Client, clusterId int32, name, target string, timeout time.Duration) (string, error) {
+	pending := []string{addonStatusInstalling, addonStatusUninstalling, addonStatusActive, addonStatusInactive}
+	conf := &waiter.StateChangeConf{
+		Pending: slices.DeleteFunc(pending, func(status string) bool {
+			return status == target
+		}),
+		Target:  []string{target},
+		Failure: []string{addonStatusError, addonStatusFailed},
+		Timeout: timeout,
+		Delay:   addonWaitDelay,
+		Refresh: func(ctx context.Context) (string, error) {
+			detailRes, httpRes, err := apiClient.AddOnApi.GetDetailAddon(ctx, clusterId, name)
+			if client.IsNotFound(httpRes, err) {
+				return addonStatusInactive, nil
+			}
+			if err != nil {
+				return "", client.NewAPIError(httpRes, err)
+			}
+			return detailRes.Status, nil
+		},
 	}
+	return conf.WaitForState(ctx)
 }
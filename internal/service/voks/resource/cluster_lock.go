@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"terraform-provider-viettelidc/internal/client"
+	"terraform-provider-viettelidc/internal/client/voksapi"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// lockCluster serializes the operations mutating a cluster, its node groups,
+// addons and NFS storage, as the vOKS API rejects or mishandles concurrent
+// ones. Once the lock is held it waits for the cluster to leave transitional
+// states such as updating, giving up after timeout. Both waits also give up at
+// the deadline of ctx, which callers set to the timeout of the whole
+// operation, so that waiting for the lock does not extend it.
+//
+// The returned unlock must be called once the operation, and the wait for its
+// completion, are done. When the cluster does not exist the lock is returned
+// right away, leaving it to the operation to report the missing cluster.
+func lockCluster(ctx context.Context, providerData client.ProviderData, apiClient *voksapi.Client, region string, clusterId int32, timeout time.Duration) (func(), diag.Diagnostics) {
+	return lockClusterUntil(ctx, providerData, region, clusterId, "become ready", func(ctx context.Context) error {
+		conf := clusterStateChangeConf(timeout, func(ctx context.Context) (string, error) {
+			cluster, httpRes, err := apiClient.DetailCluster(ctx, clusterId)
+			if err != nil {
+				return "", client.NewAPIError(httpRes, err)
+			}
+			return cluster.Status, nil
+		})
+		// Nothing has been requested yet for the API to pick up
+		conf.Delay = 0
+		_, err := conf.WaitForState(ctx)
+		return err
+	})
+}
+
+// lockClusterForDeletion is lockCluster for the deletions, which only wait
+// for the cluster to leave transitional states: a cluster in error, its node
+// groups and addons can still be deleted.
+func lockClusterForDeletion(ctx context.Context, providerData client.ProviderData, apiClient *voksapi.Client, region string, clusterId int32, timeout time.Duration) (func(), diag.Diagnostics) {
+	return lockClusterUntil(ctx, providerData, region, clusterId, "settle", func(ctx context.Context) error {
+		_, err := waitForClusterSettled(ctx, apiClient, clusterId, timeout, 0)
+		return err
+	})
+}
+
+// lockClusterUntil takes the lock of the cluster, then calls wait, describing
+// what the cluster is waited for to do by waitFor.
+func lockClusterUntil(ctx context.Context, providerData client.ProviderData, region string, clusterId int32, waitFor string, wait func(ctx context.Context) error) (func(), diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if region == "" {
+		region = providerData.Region()
+	}
+	key := fmt.Sprintf("voks/%s/cluster/%d", region, clusterId)
+
+	tflog.Debug(ctx, "Waiting for the other operations on the Cluster", map[string]any{"cluster_id": clusterId})
+	unlock, err := providerData.Lock(ctx, key)
+	if err != nil {
+		diags.AddError(
+			"Error waiting for Cluster",
+			fmt.Sprintf("Could not wait for the other operations on Cluster %d to finish, unexpected error: %s", clusterId, err))
+		return nil, diags
+	}
+
+	if err := wait(ctx); err != nil {
+		var apiErr *client.APIError
+		if errors.As(err, &apiErr) && apiErr.Kind == client.ErrorKindNotFound {
+			return unlock, diags
+		}
+		unlock()
+		diags.Append(client.ErrorDiagnostics(
+			"Error waiting for Cluster",
+			fmt.Sprintf("Could not wait for Cluster %d to %s", clusterId, waitFor),
+			nil, err, nil)...)
+		return nil, diags
+	}
+
+	return unlock, diags
+}
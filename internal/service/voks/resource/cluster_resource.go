@@ -6,17 +6,27 @@ package resource
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/viettelidc-provider/viettelidc-api-client-go/service/voks"
-	"github.com/viettelidc-provider/viettelidc-api-client-go/viettelidc"
+	"slices"
 	"strconv"
 	"strings"
+	"terraform-provider-viettelidc/internal/client"
+	"terraform-provider-viettelidc/internal/client/voksapi"
+	"terraform-provider-viettelidc/internal/waiter"
 	"time"
 )
 
@@ -24,26 +34,78 @@ var (
 	_ resource.Resource                = &clusterResource{}
 	_ resource.ResourceWithConfigure   = &clusterResource{}
 	_ resource.ResourceWithImportState = &clusterResource{}
+	_ resource.ResourceWithModifyPlan  = &clusterResource{}
 )
 
+const (
+	clusterDefaultCreateTimeout = 60 * time.Minute
+	clusterDefaultUpdateTimeout = 60 * time.Minute
+	clusterDefaultDeleteTimeout = 60 * time.Minute
+
+	clusterStatusCreating  = "CREATING"
+	clusterStatusUpgrading = "UPGRADING"
+	clusterStatusDeleting  = "DELETING"
+	// A ready Cluster is reported as either SUCCESS or POWER_ON.
+	clusterStatusSuccess = "SUCCESS"
+	clusterStatusPowerOn = "POWER_ON"
+
+	clusterStatusDeleted = "DELETED"
+	// clusterStatusSettled stands for any status but the transitional ones,
+	// such as a ready Cluster or one in error, while waiting for a Cluster
+	// to settle.
+	clusterStatusSettled = "SETTLED"
+
+	nfsStatusPoweredOn = "POWERED_ON"
+	nfsStatusCreating  = "CREATING"
+	nfsStatusUpdating  = "UPDATING"
+	nfsStatusDeleting  = "DELETING"
+	nfsStatusDeleted   = "DELETED"
+
+	// clusterWaitDelay leaves the API time to pick up a change of a Cluster,
+	// or of its NFS Storage, before it is first read.
+	clusterWaitDelay = 5 * time.Second
+)
+
+// clusterTransitionalStatuses are reported while the Cluster is being changed.
+var clusterTransitionalStatuses = []string{"CREATING", "UPDATING", "UPGRADING", "DELETING"}
+
+// clusterFieldPaths maps the fields of the cluster requests to the attributes
+// they are configured by.
+var clusterFieldPaths = client.FieldPaths{
+	"clusterId":                path.Root("id"),
+	"name":                     path.Root("name"),
+	"version":                  path.Root("version"),
+	"controlPlaneResourceType": path.Root("control_plane_resource_type"),
+	"vpcId":                    path.Root("vpc_config").AtName("vpc_id"),
+	"subnetIds":                path.Root("vpc_config").AtName("subnet_ids"),
+	"securityGroupIds":         path.Root("vpc_config").AtName("security_group_ids"),
+	"addOnsStorage":            path.Root("nfs").AtName("additional_storage_size"),
+}
+
 type clusterResource struct {
-	client *voks.APIClient
+	client client.ProviderData
 }
 
 type ClusterResourceModel struct {
-	ID        types.Int32     `tfsdk:"id"`
-	Name      types.String    `tfsdk:"name"`
-	Status    types.String    `tfsdk:"status"`
-	Version   types.String    `tfsdk:"version"`
-	Endpoint  types.String    `tfsdk:"endpoint"`
-	Nfs       *NfsBlock       `tfsdk:"nfs"`
-	VpcConfig *VpcConfigBlock `tfsdk:"vpc_config"`
+	ID                       types.Int32     `tfsdk:"id"`
+	Name                     types.String    `tfsdk:"name"`
+	Status                   types.String    `tfsdk:"status"`
+	Version                  types.String    `tfsdk:"version"`
+	ControlPlaneResourceType types.String    `tfsdk:"control_plane_resource_type"`
+	UpgradeNodeGroups        types.Bool      `tfsdk:"upgrade_node_groups"`
+	Endpoint                 types.String    `tfsdk:"endpoint"`
+	Nfs                      *NfsBlock       `tfsdk:"nfs"`
+	VpcConfig                *VpcConfigBlock `tfsdk:"vpc_config"`
+	DeletionProtection       types.Bool      `tfsdk:"deletion_protection"`
+	ForceDelete              types.Bool      `tfsdk:"force_delete"`
+	Region                   types.String    `tfsdk:"region"`
+	Timeouts                 timeouts.Value  `tfsdk:"timeouts"`
 }
 
 type VpcConfigBlock struct {
-	VpcId types.Int32 `tfsdk:"vpc_id"`
-	//SecurityGroupIds types.List  `tfsdk:"security_group_ids"`
-	//SubnetIds        types.List  `tfsdk:"subnet_ids"`
+	VpcId            types.Int32 `tfsdk:"vpc_id"`
+	SecurityGroupIds types.List  `tfsdk:"security_group_ids"`
+	SubnetIds        types.List  `tfsdk:"subnet_ids"`
 }
 
 type NfsBlock struct {
@@ -66,17 +128,17 @@ func (c *clusterResource) Configure(ctx context.Context, request resource.Config
 		return
 	}
 
-	cfg, ok := request.ProviderData.(*viettelidc.Configuration)
+	providerClient, ok := request.ProviderData.(client.ProviderData)
 	if !ok {
 		response.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *apiclient.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected client.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 
 		return
 	}
 
-	c.client = voks.NewAPIClient(*cfg)
+	c.client = providerClient
 }
 
 func (c *clusterResource) Metadata(ctx context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
@@ -107,10 +169,22 @@ func (c *clusterResource) Schema(ctx context.Context, request resource.SchemaReq
 				Computed:    true,
 			},
 			"version": schema.StringAttribute{
-				Description: "Version of Cluster.",
+				Description: "Version of Cluster. Changing it upgrades the control plane in place, one minor version at a time, e.g. from `v1.29.8` to `v1.30.5`. Downgrades are not supported.",
 				Required:    true,
+			},
+			"upgrade_node_groups": schema.BoolAttribute{
+				Description: "Default to `false`. Set it to `true` to upgrade the Node Groups of the Cluster to the new `version` once its control plane is upgraded.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"control_plane_resource_type": schema.StringAttribute{
+				Description: "Instance type of the control plane of the Cluster. Defaults to the one chosen by the platform.",
+				Optional:    true,
+				Computed:    true,
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
 				},
 			},
 			"endpoint": schema.StringAttribute{
@@ -135,8 +209,11 @@ func (c *clusterResource) Schema(ctx context.Context, request resource.SchemaReq
 						Computed:    true,
 					},
 					"additional_storage_size": schema.Int32Attribute{
-						Description: "The additional storage allocated for NFS volumes.",
+						Description: "The additional storage allocated for NFS volumes, between 10 and 2000.",
 						Optional:    true,
+						Validators: []validator.Int32{
+							int32validator.Between(10, 2000),
+						},
 					},
 					"status": schema.StringAttribute{
 						Description: "Status of Cluster NFS Storage. When the NFS Storage is present in Terraform, its status will always be `POWER_ON`. Valid values: `POWER_ON`, `UPDATING`, `ERROR`.",
@@ -148,6 +225,25 @@ func (c *clusterResource) Schema(ctx context.Context, request resource.SchemaReq
 					},
 				},
 			},
+			"deletion_protection": schema.BoolAttribute{
+				Description: "Default to `true`. Prevents Terraform from destroying the Cluster. Set it to `false`, and apply, before destroying the Cluster.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+			},
+			"force_delete": schema.BoolAttribute{
+				Description: "Default to `false`. Set it to `true` to remove the Node Groups, installed Addons and NFS Storage of the Cluster before destroying it.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"region": schema.StringAttribute{
+				Description: "Region of the resource. Defaults to the region of the provider.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 		},
 		Blocks: map[string]schema.Block{
 			"vpc_config": schema.SingleNestedBlock{
@@ -160,79 +256,203 @@ func (c *clusterResource) Schema(ctx context.Context, request resource.SchemaReq
 							int32planmodifier.RequiresReplace(),
 						},
 					},
-					//"security_group_ids": schema.ListAttribute{
-					//	Computed:    true,
-					//	ElementType: types.Int32Type,
-					//	PlanModifiers: []planmodifier.List{
-					//		listplanmodifier.UseStateForUnknown(),
-					//	},
-					//},
-					//"subnet_ids": schema.ListAttribute{
-					//	Computed:    true,
-					//	ElementType: types.Int32Type,
-					//	PlanModifiers: []planmodifier.List{
-					//		listplanmodifier.UseStateForUnknown(),
-					//	},
-					//},
+					"security_group_ids": schema.ListAttribute{
+						Description: "The IDs of the security group to be associated with the VPC endpoint. Defaults to the ones chosen by the platform.",
+						Optional:    true,
+						Computed:    true,
+						ElementType: types.Int32Type,
+						PlanModifiers: []planmodifier.List{
+							listplanmodifier.UseStateForUnknown(),
+							listplanmodifier.RequiresReplaceIfConfigured(),
+						},
+					},
+					"subnet_ids": schema.ListAttribute{
+						Description: "The IDs of the subnets to be associated with the VPC endpoint. Defaults to the ones chosen by the platform.",
+						Optional:    true,
+						Computed:    true,
+						ElementType: types.Int32Type,
+						PlanModifiers: []planmodifier.List{
+							listplanmodifier.UseStateForUnknown(),
+							listplanmodifier.RequiresReplaceIfConfigured(),
+						},
+					},
 				},
 			},
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create:            true,
+				CreateDescription: "How long to wait for the Cluster, and its NFS Storage, to be created. Defaults to `60m`.",
+				Update:            true,
+				UpdateDescription: "How long to wait for the Cluster NFS Storage to be extended, and for the Cluster, and with `upgrade_node_groups` its Node Groups, to be upgraded. Defaults to `60m`.",
+				Delete:            true,
+				DeleteDescription: "How long to wait for the Cluster, and with `force_delete` its Node Groups, Addons and NFS Storage, to be deleted. Defaults to `60m`.",
+			}),
 		},
 	}
 }
 
+// ModifyPlan defers the change when the provider configuration is unknown
+// until apply.
+func (c *clusterResource) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	if c.client.DeferResourcePlan(ctx, request, response) || request.Plan.Raw.IsNull() {
+		return
+	}
+
+	if !request.State.Raw.IsNull() {
+		var planVersion, stateVersion types.String
+		response.Diagnostics.Append(request.Plan.GetAttribute(ctx, path.Root("version"), &planVersion)...)
+		response.Diagnostics.Append(request.State.GetAttribute(ctx, path.Root("version"), &stateVersion)...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+		if !planVersion.IsUnknown() && !planVersion.Equal(stateVersion) {
+			same, diags := validateClusterUpgrade(stateVersion.ValueString(), planVersion.ValueString())
+			response.Diagnostics.Append(diags...)
+			if same {
+				// Only the formatting differs, e.g. 1.30.5 and v1.30.5
+				response.Diagnostics.Append(response.Plan.SetAttribute(ctx, path.Root("version"), stateVersion)...)
+			}
+		}
+	}
+
+	// The NFS Storage is only created when configured, so an unconfigured
+	// `nfs` keeps its current value instead of being planned as unknown
+	var nfsConfig types.Object
+	response.Diagnostics.Append(request.Config.GetAttribute(ctx, path.Root("nfs"), &nfsConfig)...)
+	if response.Diagnostics.HasError() || !nfsConfig.IsNull() {
+		return
+	}
+
+	var nfs *NfsBlock
+	if !request.State.Raw.IsNull() {
+		response.Diagnostics.Append(request.State.GetAttribute(ctx, path.Root("nfs"), &nfs)...)
+	}
+	response.Diagnostics.Append(response.Plan.SetAttribute(ctx, path.Root("nfs"), nfs)...)
+}
+
 func (c *clusterResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
-	response.Diagnostics.AddWarning(
-		"Error creating Cluster",
-		"Could not create Cluster, this action is not supported.")
-	return
-
-	//var state ClusterResourceModel
-	//
-	//cluster, _, err := c.client.ClusterApi.DetailCluster(ctx, voks.BaseResourceReq{
-	//	ClusterId: 2459,
-	//})
-	//if err != nil {
-	//	response.Diagnostics.AddError(
-	//		"Error reading Cluster detail",
-	//		"Could not read Cluster detail, unexpected error: "+err.Error())
-	//	return
-	//}
-	//
-	//state.ID = types.Int32Value(cluster.Id)
-	//state.Name = types.StringValue(cluster.Name)
-	//state.Status = types.StringValue(cluster.Status)
-	//state.Version = types.StringValue(cluster.Version)
-	//state.Endpoint = types.StringValue(cluster.ApiAddress)
-	//state.VpcConfig = &VpcConfigBlock{
-	//	VpcId: types.Int32Value(cluster.VpcId),
-	//}
-	//
-	//nfs, _, err := c.client.NFSApi.DetailNfsStorage(ctx, voks.BaseResourceReq{
-	//	ClusterId: cluster.Id,
-	//})
-	//if err != nil {
-	//	response.Diagnostics.AddError(
-	//		"Error reading Cluster NFS detail",
-	//		"Could not read Cluster NFS detail, unexpected error: "+err.Error())
-	//	return
-	//}
-	//
-	//state.Nfs = &NfsBlock{
-	//	Cpu:              types.Float64Value(nfs.CpuSize),
-	//	Memory:           types.Float64Value(nfs.MemorySize),
-	//	TotalStorageSize: types.Float64Value(nfs.StorageSize),
-	//	Status:           types.StringValue(nfs.Status),
-	//	IpAddress:        types.StringValue(nfs.InternalIp),
-	//}
-	//
-	//diags := response.State.Set(ctx, &state)
-	//response.Diagnostics.Append(diags...)
-	//if response.Diagnostics.HasError() {
-	//	return
-	//}
+
+	var plan ClusterResourceModel
+	diags := request.Plan.Get(ctx, &plan)
+	response.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, clusterDefaultCreateTimeout)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	if plan.VpcConfig == nil {
+		response.Diagnostics.AddAttributeError(
+			path.Root("vpc_config"),
+			"Invalid Configuration",
+			"`vpc_config` must be set to create a Cluster")
+		return
+	}
+
+	apiClient, diags := c.client.Voks(ctx, plan.Region.ValueString())
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	reqBody := voksapi.CreateClusterRequest{
+		Name:                     plan.Name.ValueString(),
+		Version:                  plan.Version.ValueString(),
+		ControlPlaneResourceType: plan.ControlPlaneResourceType.ValueString(),
+		VpcConfig: voksapi.VpcConfig{
+			VpcId: plan.VpcConfig.VpcId.ValueInt32(),
+		},
+	}
+	if !plan.VpcConfig.SubnetIds.IsUnknown() {
+		response.Diagnostics.Append(plan.VpcConfig.SubnetIds.ElementsAs(ctx, &reqBody.VpcConfig.SubnetIds, false)...)
+	}
+	if !plan.VpcConfig.SecurityGroupIds.IsUnknown() {
+		response.Diagnostics.Append(plan.VpcConfig.SecurityGroupIds.ElementsAs(ctx, &reqBody.VpcConfig.SecurityGroupIds, false)...)
+	}
+	if plan.Nfs != nil {
+		reqBody.EnableNfs = true
+		reqBody.AddOnsStorage = plan.Nfs.AdditionalStorageSize.ValueInt32()
+	}
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	resBody, httpRes, err := apiClient.CreateCluster(ctx, reqBody)
+	if err != nil {
+		response.Diagnostics.Append(client.ErrorDiagnostics(
+			"Error creating Cluster",
+			"Could not create Cluster",
+			httpRes, err, clusterFieldPaths)...)
+		return
+	}
+
+	// Save the Cluster right away, so that Terraform keeps track of it when
+	// the waits below fail or are interrupted
+	plan.ID = types.Int32Value(resBody.Id)
+	plan.Status = types.StringValue(clusterStatusCreating)
+	nullUnknownClusterValues(&plan)
+	response.Diagnostics.Append(response.State.Set(ctx, &plan)...)
+	response.Diagnostics.Append(markCreateInProgress(ctx, response.Private)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	var cluster voksapi.ClusterDetail
+	conf := clusterStateChangeConf(createTimeout, func(ctx context.Context) (string, error) {
+		detail, httpRes, err := apiClient.DetailCluster(ctx, resBody.Id)
+		if err != nil {
+			return "", client.NewAPIError(httpRes, err)
+		}
+		cluster = detail
+		return detail.Status, nil
+	})
+	_, err = conf.WaitForState(ctx)
+	if err == nil && plan.Nfs != nil {
+		conf = nfsStateChangeConf(createTimeout, func(ctx context.Context) (string, error) {
+			nfs, httpRes, err := apiClient.NFSApi.DetailNfsStorage(client.WithRetrySafe(ctx), voks.BaseResourceReq{
+				ClusterId: resBody.Id,
+			})
+			if err != nil {
+				return "", client.NewAPIError(httpRes, err)
+			}
+			return nfs.Status, nil
+		})
+		_, err = conf.WaitForState(ctx)
+	}
+	if err != nil {
+		response.Diagnostics.Append(client.ErrorDiagnostics(
+			"Error waiting for Cluster creation",
+			"Could not wait for Cluster to power on",
+			nil, err, nil)...)
+		response.Diagnostics.Append(createFailedDiagnostic("Cluster", strconv.Itoa(int(resBody.Id))))
+		return
+	}
+
+	response.Diagnostics.Append(setClusterModel(ctx, apiClient, cluster, &plan)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	response.Diagnostics.Append(clearCreateInProgress(ctx, response.Private)...)
+
+	// Set state to fully populated data
+	diags = response.State.Set(ctx, &plan)
+	response.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
 }
 
 func (c *clusterResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
+	if c.client.DeferResourceRead(request, response) {
+		return
+	}
 
 	var state ClusterResourceModel
 	diags := request.State.Get(ctx, &state)
@@ -241,39 +461,38 @@ func (c *clusterResource) Read(ctx context.Context, request resource.ReadRequest
 		return
 	}
 
-	cluster, _, err := c.client.ClusterApi.DetailCluster(ctx, state.ID.ValueInt32())
-	if err != nil {
-		response.Diagnostics.AddError(
-			"Error reading Cluster detail",
-			"Could not read Cluster detail, unexpected error: "+err.Error())
+	apiClient, diags := c.client.Voks(ctx, state.Region.ValueString())
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
 		return
 	}
 
-	state.ID = types.Int32Value(cluster.Id)
-	state.Name = types.StringValue(cluster.Name)
-	state.Status = types.StringValue(cluster.Status)
-	state.Version = types.StringValue(cluster.Version)
-	state.Endpoint = types.StringValue(cluster.ApiAddress)
-	state.VpcConfig = &VpcConfigBlock{
-		VpcId: types.Int32Value(cluster.VpcConfig.VpcId),
+	cluster, httpRes, err := apiClient.DetailCluster(ctx, state.ID.ValueInt32())
+	if client.IsNotFound(httpRes, err) {
+		response.State.RemoveResource(ctx)
+		return
 	}
-
-	nfs, _, err := c.client.NFSApi.DetailNfsStorage(ctx, voks.BaseResourceReq{
-		ClusterId: cluster.Id,
-	})
 	if err != nil {
-		response.Diagnostics.AddError(
-			"Error reading Cluster NFS detail",
-			"Could not read Cluster NFS detail, unexpected error: "+err.Error())
+		response.Diagnostics.Append(client.ErrorDiagnostics(
+			"Error reading Cluster detail",
+			"Could not read Cluster detail",
+			httpRes, err, nil)...)
 		return
 	}
 
-	state.Nfs = &NfsBlock{
-		Cpu:              types.Float64Value(nfs.CpuSize),
-		Memory:           types.Float64Value(nfs.MemorySize),
-		TotalStorageSize: types.Float64Value(nfs.StorageSize),
-		Status:           types.StringValue(nfs.Status),
-		IpAddress:        types.StringValue(nfs.InternalIp),
+	response.Diagnostics.Append(setClusterModel(ctx, apiClient, cluster, &state)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// A Cluster saved before it powered on is either ready by now, or still
+	// tainted and about to be replaced.
+	inProgress, diags := isCreateInProgress(ctx, request.Private)
+	response.Diagnostics.Append(diags...)
+	if inProgress && isClusterReady(cluster.Status) {
+		response.Diagnostics.Append(clearCreateInProgress(ctx, response.Private)...)
+	} else if inProgress {
+		response.Diagnostics.Append(createInProgressDiagnostic("Cluster", strconv.Itoa(int(cluster.Id)), cluster.Status))
 	}
 
 	diags = response.State.Set(ctx, &state)
@@ -284,6 +503,9 @@ func (c *clusterResource) Read(ctx context.Context, request resource.ReadRequest
 }
 
 func (c *clusterResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	if c.client.DeferImportState(request, response) {
+		return
+	}
 
 	id, err := strconv.ParseInt(request.ID, 10, 32)
 	if err != nil {
@@ -294,6 +516,8 @@ func (c *clusterResource) ImportState(ctx context.Context, request resource.Impo
 	}
 
 	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("id"), id)...)
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("deletion_protection"), true)...)
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("force_delete"), false)...)
 }
 
 func (c *clusterResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
@@ -302,29 +526,31 @@ func (c *clusterResource) Update(ctx context.Context, request resource.UpdateReq
 	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
 	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
 
-	if state.Name != plan.Name {
-		response.Diagnostics.AddError(
-			"Error updating Cluster",
-			"Could not update field `name` of the Cluster, this action is not supported.")
+	updateTimeout, diags := plan.Timeouts.Update(ctx, clusterDefaultUpdateTimeout)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
 		return
 	}
 
-	if state.Version != plan.Version {
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	apiClient, diags := c.client.Voks(ctx, state.Region.ValueString())
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	if state.Name != plan.Name {
 		response.Diagnostics.AddError(
 			"Error updating Cluster",
-			"Could not update field `version` of the Cluster, this action is not supported.")
+			"Could not update field `name` of the Cluster, this action is not supported.")
 		return
 	}
 
 	isUpdateNfs := false
 	if state.Nfs == nil || state.Nfs.AdditionalStorageSize.IsNull() {
 		if plan.Nfs != nil && !plan.Nfs.AdditionalStorageSize.IsNull() {
-			if plan.Nfs.AdditionalStorageSize.ValueInt32() < 10 || plan.Nfs.AdditionalStorageSize.ValueInt32() > 2000 {
-				response.Diagnostics.AddError(
-					"Error updating Cluster",
-					"Could not update field `nfs.additional_storage_size` of the Cluster, value must be between 10 and 2000.")
-				return
-			}
 			isUpdateNfs = true
 		}
 	} else {
@@ -340,82 +566,620 @@ func (c *clusterResource) Update(ctx context.Context, request resource.UpdateReq
 				"Could not update field `nfs.additional_storage_size` of the Cluster, value must be equal or greater than old value.")
 			return
 		}
-		if plan.Nfs.AdditionalStorageSize.ValueInt32() > 2000 {
-			response.Diagnostics.AddError(
-				"Error updating Cluster",
-				"Could not update field `nfs.additional_storage_size` of the Cluster, value must be between 10 and 2000.")
-			return
-		}
 		if plan.Nfs.AdditionalStorageSize.ValueInt32() > state.Nfs.AdditionalStorageSize.ValueInt32() {
 			isUpdateNfs = true
 		}
 	}
 
+	isUpgrade := !plan.Version.Equal(state.Version)
+
+	if isUpdateNfs || isUpgrade {
+		unlock, diags := lockCluster(ctx, c.client, apiClient, state.Region.ValueString(), state.ID.ValueInt32(), updateTimeout)
+		response.Diagnostics.Append(diags...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+		defer unlock()
+	}
+
 	if isUpdateNfs {
-		_, err := c.client.NFSApi.ExtendNfsStorage(ctx, voks.AddonNfsRequest{
+		httpRes, err := apiClient.NFSApi.ExtendNfsStorage(ctx, voks.AddonNfsRequest{
 			ClusterId:     plan.ID.ValueInt32(),
 			AddOnsStorage: plan.Nfs.AdditionalStorageSize.ValueInt32(),
 		})
 		if err != nil {
-			response.Diagnostics.AddError(
+			response.Diagnostics.Append(client.ErrorDiagnostics(
 				"Error updating Cluster",
-				"Could not update NFS Storage of Cluster, unexpected error: "+err.Error())
+				"Could not update NFS Storage of Cluster",
+				httpRes, err, clusterFieldPaths)...)
 			return
 		}
-		for {
-			nfs, _, err := c.client.NFSApi.DetailNfsStorage(ctx, voks.BaseResourceReq{
+		// The NFS Storage may still be reported powered on at its previous
+		// size until the API starts extending it, so it is only extended
+		// once it is powered on at a larger size.
+		var previousSize float64
+		if state.Nfs != nil {
+			previousSize = state.Nfs.TotalStorageSize.ValueFloat64()
+		}
+		conf := nfsStateChangeConf(updateTimeout, func(ctx context.Context) (string, error) {
+			nfs, httpRes, err := apiClient.NFSApi.DetailNfsStorage(client.WithRetrySafe(ctx), voks.BaseResourceReq{
 				ClusterId: plan.ID.ValueInt32(),
 			})
 			if err != nil {
-				response.Diagnostics.AddError(
-					"Error updating Cluster NFS status",
-					"Could not read Cluster NFS detail, unexpected error: "+err.Error())
-				return
-			}
-			if strings.EqualFold(nfs.Status, "POWERED_ON") {
-				plan.Nfs.Cpu = types.Float64Value(nfs.CpuSize)
-				plan.Nfs.Memory = types.Float64Value(nfs.MemorySize)
-				plan.Nfs.TotalStorageSize = types.Float64Value(nfs.StorageSize)
-				plan.Nfs.Status = types.StringValue(nfs.Status)
-				plan.Nfs.IpAddress = types.StringValue(nfs.InternalIp)
-				break
+				return "", client.NewAPIError(httpRes, err)
 			}
-			if strings.EqualFold(nfs.Status, "ERROR") {
-				response.Diagnostics.AddError(
-					"Error extending Cluster NFS Storage",
-					"Could not extend Cluster NFS Storage.")
-				return
+			if strings.EqualFold(nfs.Status, nfsStatusPoweredOn) && nfs.StorageSize <= previousSize {
+				return nfsStatusUpdating, nil
 			}
-			time.Sleep(10 * time.Second)
+			return nfs.Status, nil
+		})
+		if _, err := conf.WaitForState(ctx); err != nil {
+			response.Diagnostics.Append(client.ErrorDiagnostics(
+				"Error extending Cluster NFS Storage",
+				"Could not wait for Cluster NFS Storage to power on",
+				nil, err, nil)...)
+			return
+		}
+	}
+
+	if isUpgrade {
+		response.Diagnostics.Append(upgradeCluster(ctx, apiClient, state.ID.ValueInt32(), plan.Version.ValueString(), plan.UpgradeNodeGroups.ValueBool(), updateTimeout)...)
+		if response.Diagnostics.HasError() {
+			return
 		}
 	}
 
 	// Update cluster detail
-	cluster, _, err := c.client.ClusterApi.DetailCluster(ctx, state.ID.ValueInt32())
+	cluster, httpRes, err := apiClient.DetailCluster(ctx, state.ID.ValueInt32())
 	if err != nil {
-		response.Diagnostics.AddError(
+		response.Diagnostics.Append(client.ErrorDiagnostics(
 			"Error reading Cluster detail",
-			"Could not read Cluster detail, unexpected error: "+err.Error())
+			"Could not read Cluster detail",
+			httpRes, err, nil)...)
 		return
 	}
 
-	plan.Name = types.StringValue(cluster.Name)
-	plan.Status = types.StringValue(cluster.Status)
-	plan.Version = types.StringValue(cluster.Version)
-	plan.Endpoint = types.StringValue(cluster.ApiAddress)
-	plan.VpcConfig = &VpcConfigBlock{
-		VpcId: types.Int32Value(cluster.VpcConfig.VpcId),
+	response.Diagnostics.Append(setClusterModel(ctx, apiClient, cluster, &plan)...)
+	if response.Diagnostics.HasError() {
+		return
 	}
 
 	response.Diagnostics.Append(response.State.Set(ctx, &plan)...)
 	if response.Diagnostics.HasError() {
 		return
 	}
+
 }
 
 func (c *clusterResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
-	response.Diagnostics.AddWarning(
-		"Error deleting Cluster",
-		"Could not delete Cluster, this action is not supported.")
-	return
+
+	var state ClusterResourceModel
+	diags := request.State.Get(ctx, &state)
+	response.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+
+	if state.DeletionProtection.ValueBool() {
+		response.Diagnostics.AddAttributeError(
+			path.Root("deletion_protection"),
+			"Error deleting Cluster",
+			fmt.Sprintf("Could not delete Cluster %d, `deletion_protection` is enabled. "+
+				"Set `deletion_protection = false`, and apply, before destroying the Cluster.", state.ID.ValueInt32()))
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, clusterDefaultDeleteTimeout)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	apiClient, diags := c.client.Voks(ctx, state.Region.ValueString())
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	clusterId := state.ID.ValueInt32()
+	unlock, diags := lockClusterForDeletion(ctx, c.client, apiClient, state.Region.ValueString(), clusterId, deleteTimeout)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	defer unlock()
+
+	if state.ForceDelete.ValueBool() {
+		response.Diagnostics.Append(emptyCluster(ctx, apiClient, clusterId, deleteTimeout)...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	httpRes, err := apiClient.DeleteCluster(ctx, voks.BaseResourceReq{
+		ClusterId: clusterId,
+	})
+	if client.IsNotFound(httpRes, err) {
+		// Already deleted outside of Terraform
+		return
+	}
+	if err != nil {
+		response.Diagnostics.Append(client.ErrorDiagnostics(
+			"Error deleting Cluster",
+			"Could not delete Cluster",
+			httpRes, err, clusterFieldPaths)...)
+		return
+	}
+
+	// Until the API reports the Cluster as deleting, it may still report its
+	// previous status, so an error status only fails the wait once the
+	// deletion has started.
+	deleting := false
+	conf := &waiter.StateChangeConf{
+		Pending: []string{clusterStatusDeleting, clusterStatusSuccess, clusterStatusPowerOn, "POWER_OFF", "ERROR", "FAILED"},
+		Target:  []string{clusterStatusDeleted},
+		Timeout: deleteTimeout,
+		Delay:   clusterWaitDelay,
+		Refresh: func(ctx context.Context) (string, error) {
+			cluster, httpRes, err := apiClient.DetailCluster(ctx, clusterId)
+			if client.IsNotFound(httpRes, err) {
+				return clusterStatusDeleted, nil
+			}
+			if err != nil {
+				return "", client.NewAPIError(httpRes, err)
+			}
+			switch {
+			case strings.EqualFold(cluster.Status, clusterStatusDeleting):
+				deleting = true
+			case deleting && (strings.EqualFold(cluster.Status, "ERROR") || strings.EqualFold(cluster.Status, "FAILED")):
+				return cluster.Status, &waiter.FailureStateError{State: cluster.Status}
+			}
+			return cluster.Status, nil
+		},
+	}
+	if _, err := conf.WaitForState(ctx); err != nil {
+		response.Diagnostics.Append(client.ErrorDiagnostics(
+			"Error waiting for Cluster deletion",
+			"Could not wait for Cluster to be deleted",
+			nil, err, nil)...)
+		return
+	}
+}
+
+// emptyCluster removes the node groups, installed addons and NFS Storage of
+// a cluster, waiting for each removal to complete. The caller holds the lock
+// of the cluster.
+func emptyCluster(ctx context.Context, apiClient *voksapi.Client, clusterId int32, timeout time.Duration) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	nodeGroups, httpRes, err := apiClient.GetAllNodeGroup(ctx, clusterId)
+	if err != nil {
+		diags.Append(client.ErrorDiagnostics(
+			"Error deleting Cluster",
+			"Could not list the Node Groups of the Cluster",
+			httpRes, err, nil)...)
+		return diags
+	}
+	for _, nodeGroup := range nodeGroups {
+		tflog.Debug(ctx, "Deleting Cluster Node Group", map[string]any{"cluster_id": clusterId, "id": nodeGroup.Id})
+		httpRes, err := apiClient.NodeGroupApi.DeleteNodeGroup(ctx, voks.DeleteNodeGroupRequest{
+			ClusterId: clusterId,
+			Id:        nodeGroup.Id,
+		})
+		if err != nil && !client.IsNotFound(httpRes, err) {
+			diags.Append(client.ErrorDiagnostics(
+				"Error deleting Cluster",
+				fmt.Sprintf("Could not delete Node Group %d of the Cluster", nodeGroup.Id),
+				httpRes, err, nil)...)
+			return diags
+		}
+		err = waitForNodeGroupDeleted(ctx, apiClient, clusterId, nodeGroup.Id, timeout)
+		if err == nil {
+			_, err = waitForClusterSettled(ctx, apiClient, clusterId, timeout, clusterWaitDelay)
+		}
+		if err != nil {
+			diags.Append(client.ErrorDiagnostics(
+				"Error deleting Cluster",
+				fmt.Sprintf("Could not wait for Node Group %d of the Cluster to be deleted", nodeGroup.Id),
+				nil, err, nil)...)
+			return diags
+		}
+	}
+
+	addons, httpRes, err := apiClient.GetAllInstalledAddon(ctx, clusterId)
+	if err != nil {
+		diags.Append(client.ErrorDiagnostics(
+			"Error deleting Cluster",
+			"Could not list the Addons installed on the Cluster",
+			httpRes, err, nil)...)
+		return diags
+	}
+	for _, addon := range addons {
+		if strings.EqualFold(addon.Status, addonStatusInactive) {
+			continue
+		}
+		tflog.Debug(ctx, "Uninstalling Cluster Addon", map[string]any{"cluster_id": clusterId, "name": addon.Name})
+		httpRes, err := apiClient.AddOnApi.UninstallAddOn(ctx, voks.AddonUninstallRequest{
+			ClusterId: clusterId,
+			Name:      addon.Name,
+		})
+		if err != nil && !client.IsNotFound(httpRes, err) {
+			diags.Append(client.ErrorDiagnostics(
+				"Error deleting Cluster",
+				fmt.Sprintf("Could not uninstall Addon %s from the Cluster", addon.Name),
+				httpRes, err, nil)...)
+			return diags
+		}
+		if _, err := waitForAddonStatus(ctx, apiClient, clusterId, addon.Name, addonStatusInactive, timeout); err != nil {
+			diags.Append(client.ErrorDiagnostics(
+				"Error deleting Cluster",
+				fmt.Sprintf("Could not wait for Addon %s to be uninstalled from the Cluster", addon.Name),
+				nil, err, nil)...)
+			return diags
+		}
+	}
+
+	tflog.Debug(ctx, "Deleting Cluster NFS Storage", map[string]any{"cluster_id": clusterId})
+	httpRes, err = apiClient.DeleteNfsStorage(ctx, voks.BaseResourceReq{
+		ClusterId: clusterId,
+	})
+	if client.IsNotFound(httpRes, err) {
+		// The Cluster has no NFS Storage
+		return diags
+	}
+	if err != nil {
+		diags.Append(client.ErrorDiagnostics(
+			"Error deleting Cluster",
+			"Could not delete the NFS Storage of the Cluster",
+			httpRes, err, nil)...)
+		return diags
+	}
+	conf := &waiter.StateChangeConf{
+		Pending: []string{nfsStatusDeleting, nfsStatusPoweredOn},
+		Target:  []string{nfsStatusDeleted},
+		Failure: []string{"ERROR"},
+		Timeout: timeout,
+		Delay:   clusterWaitDelay,
+		Refresh: func(ctx context.Context) (string, error) {
+			nfs, httpRes, err := apiClient.NFSApi.DetailNfsStorage(client.WithRetrySafe(ctx), voks.BaseResourceReq{
+				ClusterId: clusterId,
+			})
+			if client.IsNotFound(httpRes, err) {
+				return nfsStatusDeleted, nil
+			}
+			if err != nil {
+				return "", client.NewAPIError(httpRes, err)
+			}
+			return nfs.Status, nil
+		},
+	}
+	if _, err := conf.WaitForState(ctx); err != nil {
+		diags.Append(client.ErrorDiagnostics(
+			"Error deleting Cluster",
+			"Could not wait for the NFS Storage of the Cluster to be deleted",
+			nil, err, nil)...)
+		return diags
+	}
+	return diags
+}
+
+// upgradeCluster upgrades the control plane of a cluster to version, then its
+// node groups one at a time when upgradeNodeGroups is set, waiting for each
+// upgrade to complete. The caller holds the lock of the cluster.
+func upgradeCluster(ctx context.Context, apiClient *voksapi.Client, clusterId int32, version string, upgradeNodeGroups bool, timeout time.Duration) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// The Cluster may still be reported ready at its previous version until
+	// the API starts the upgrade, so it is only upgraded once it is ready at
+	// the new version.
+	waitForCluster := func() error {
+		conf := clusterStateChangeConf(timeout, func(ctx context.Context) (string, error) {
+			cluster, httpRes, err := apiClient.DetailCluster(ctx, clusterId)
+			if err != nil {
+				return "", client.NewAPIError(httpRes, err)
+			}
+			if isClusterReady(cluster.Status) && !sameKubernetesVersion(cluster.Version, version) {
+				return clusterStatusUpgrading, nil
+			}
+			return cluster.Status, nil
+		})
+		_, err := conf.WaitForState(ctx)
+		return err
+	}
+
+	tflog.Debug(ctx, "Upgrading Cluster", map[string]any{"cluster_id": clusterId, "version": version})
+	httpRes, err := apiClient.UpgradeCluster(ctx, voksapi.UpgradeClusterRequest{
+		ClusterId: clusterId,
+		Version:   version,
+	})
+	if err != nil {
+		diags.Append(client.ErrorDiagnostics(
+			"Error upgrading Cluster",
+			"Could not upgrade Cluster",
+			httpRes, err, clusterFieldPaths)...)
+		return diags
+	}
+	if err := waitForCluster(); err != nil {
+		diags.Append(client.ErrorDiagnostics(
+			"Error waiting for Cluster upgrade",
+			"Could not wait for Cluster to be upgraded",
+			nil, err, nil)...)
+		return diags
+	}
+
+	if !upgradeNodeGroups {
+		return diags
+	}
+
+	nodeGroups, httpRes, err := apiClient.GetAllNodeGroup(ctx, clusterId)
+	if err != nil {
+		diags.Append(client.ErrorDiagnostics(
+			"Error upgrading Cluster",
+			"Could not list the Node Groups of the Cluster",
+			httpRes, err, nil)...)
+		return diags
+	}
+	for _, nodeGroup := range nodeGroups {
+		tflog.Debug(ctx, "Upgrading Cluster Node Group", map[string]any{"cluster_id": clusterId, "id": nodeGroup.Id})
+		httpRes, err := apiClient.UpgradeNodeGroup(ctx, voksapi.UpgradeNodeGroupRequest{
+			ClusterId: clusterId,
+			Id:        nodeGroup.Id,
+		})
+		if err != nil {
+			diags.Append(client.ErrorDiagnostics(
+				"Error upgrading Cluster",
+				fmt.Sprintf("Could not upgrade Node Group %d of the Cluster", nodeGroup.Id),
+				httpRes, err, nil)...)
+			return diags
+		}
+
+		conf := nodeGroupStateChangeConf(timeout, func(ctx context.Context) (string, error) {
+			detail, httpRes, err := apiClient.NodeGroupApi.DetailNodeGroup(ctx, clusterId, nodeGroup.Id)
+			if err != nil {
+				return "", client.NewAPIError(httpRes, err)
+			}
+			return detail.Status, nil
+		})
+		_, err = conf.WaitForState(ctx)
+		if err == nil {
+			err = waitForCluster()
+		}
+		if err != nil {
+			diags.Append(client.ErrorDiagnostics(
+				"Error upgrading Cluster",
+				fmt.Sprintf("Could not wait for Node Group %d of the Cluster to be upgraded", nodeGroup.Id),
+				nil, err, nil)...)
+			return diags
+		}
+	}
+	return diags
+}
+
+// validateClusterUpgrade returns the errors of an upgrade of the control plane
+// from the current to the target Kubernetes version, which only supports
+// moving to the same or the next minor version. It reports whether both are
+// the same version.
+func validateClusterUpgrade(current, target string) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	targetVersion, err := parseKubernetesVersion(target)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("version"),
+			"Invalid Configuration",
+			err.Error())
+		return false, diags
+	}
+	currentVersion, err := parseKubernetesVersion(current)
+	if err != nil {
+		// Leave it to the API to validate the upgrade
+		return false, diags
+	}
+
+	switch {
+	case targetVersion == currentVersion:
+		return true, diags
+	case targetVersion.less(currentVersion):
+		diags.AddAttributeError(
+			path.Root("version"),
+			"Invalid Configuration",
+			fmt.Sprintf("Could not downgrade the Cluster from %s to %s, downgrades are not supported.", current, target))
+	case targetVersion.major != currentVersion.major || targetVersion.minor > currentVersion.minor+1:
+		diags.AddAttributeError(
+			path.Root("version"),
+			"Invalid Configuration",
+			fmt.Sprintf("Could not upgrade the Cluster from %s to %s, the control plane can only be upgraded one minor version at a time. "+
+				"Upgrade it to v%d.%d first.", current, target, currentVersion.major, currentVersion.minor+1))
+	}
+	return false, diags
+}
+
+// kubernetesVersion is a Kubernetes version such as v1.30.5.
+type kubernetesVersion struct {
+	major, minor, patch int
+}
+
+func parseKubernetesVersion(s string) (kubernetesVersion, error) {
+	var version kubernetesVersion
+	parts := strings.Split(strings.TrimPrefix(s, "v"), ".")
+	if len(parts) != 3 {
+		return version, fmt.Errorf("%q is not a Kubernetes version, expected a version such as v1.30.5", s)
+	}
+	for i, target := range []*int{&version.major, &version.minor, &version.patch} {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil || n < 0 {
+			return version, fmt.Errorf("%q is not a Kubernetes version, expected a version such as v1.30.5", s)
+		}
+		*target = n
+	}
+	return version, nil
+}
+
+// sameKubernetesVersion reports whether both are the same Kubernetes version,
+// e.g. 1.30.5 and v1.30.5.
+func sameKubernetesVersion(a, b string) bool {
+	return strings.TrimPrefix(a, "v") == strings.TrimPrefix(b, "v")
+}
+
+func (v kubernetesVersion) less(other kubernetesVersion) bool {
+	if v.major != other.major {
+		return v.major < other.major
+	}
+	if v.minor != other.minor {
+		return v.minor < other.minor
+	}
+	return v.patch < other.patch
+}
+
+// setClusterModel sets model to cluster, and to the detail of its NFS Storage
+// when the Cluster has one. The configured `nfs.additional_storage_size`,
+// which the API does not return, is kept.
+func setClusterModel(ctx context.Context, apiClient *voksapi.Client, cluster voksapi.ClusterDetail, model *ClusterResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	model.ID = types.Int32Value(cluster.Id)
+	model.Name = types.StringValue(cluster.Name)
+	model.Status = types.StringValue(cluster.Status)
+	model.Version = types.StringValue(cluster.Version)
+	model.ControlPlaneResourceType = types.StringValue(cluster.ControlPlaneResourceType)
+	model.Endpoint = types.StringValue(cluster.ApiAddress)
+
+	securityGroupIds, d := types.ListValueFrom(ctx, types.Int32Type, cluster.VpcConfig.SecurityGroupIds)
+	diags.Append(d...)
+	subnetIds, d := types.ListValueFrom(ctx, types.Int32Type, cluster.VpcConfig.SubnetIds)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+	model.VpcConfig = &VpcConfigBlock{
+		VpcId:            types.Int32Value(cluster.VpcConfig.VpcId),
+		SecurityGroupIds: securityGroupIds,
+		SubnetIds:        subnetIds,
+	}
+
+	nfs, httpRes, err := apiClient.NFSApi.DetailNfsStorage(client.WithRetrySafe(ctx), voks.BaseResourceReq{
+		ClusterId: cluster.Id,
+	})
+	if client.IsNotFound(httpRes, err) {
+		model.Nfs = nil
+		return diags
+	}
+	if err != nil {
+		diags.Append(client.ErrorDiagnostics(
+			"Error reading Cluster NFS detail",
+			"Could not read Cluster NFS detail",
+			httpRes, err, nil)...)
+		return diags
+	}
+
+	additionalStorageSize := types.Int32Null()
+	if model.Nfs != nil {
+		additionalStorageSize = model.Nfs.AdditionalStorageSize
+	}
+	model.Nfs = &NfsBlock{
+		Cpu:                   types.Float64Value(nfs.CpuSize),
+		Memory:                types.Float64Value(nfs.MemorySize),
+		TotalStorageSize:      types.Float64Value(nfs.StorageSize),
+		AdditionalStorageSize: additionalStorageSize,
+		Status:                types.StringValue(nfs.Status),
+		IpAddress:             types.StringValue(nfs.InternalIp),
+	}
+	return diags
+}
+
+// nullUnknownClusterValues sets the computed values of model still unknown
+// to null, as they are only known once the Cluster is ready and a state
+// cannot hold unknown values.
+func nullUnknownClusterValues(model *ClusterResourceModel) {
+	if model.ControlPlaneResourceType.IsUnknown() {
+		model.ControlPlaneResourceType = types.StringNull()
+	}
+	if model.Endpoint.IsUnknown() {
+		model.Endpoint = types.StringNull()
+	}
+	if model.VpcConfig != nil {
+		if model.VpcConfig.SecurityGroupIds.IsUnknown() {
+			model.VpcConfig.SecurityGroupIds = types.ListNull(types.Int32Type)
+		}
+		if model.VpcConfig.SubnetIds.IsUnknown() {
+			model.VpcConfig.SubnetIds = types.ListNull(types.Int32Type)
+		}
+	}
+	if model.Nfs != nil {
+		if model.Nfs.Cpu.IsUnknown() {
+			model.Nfs.Cpu = types.Float64Null()
+		}
+		if model.Nfs.Memory.IsUnknown() {
+			model.Nfs.Memory = types.Float64Null()
+		}
+		if model.Nfs.TotalStorageSize.IsUnknown() {
+			model.Nfs.TotalStorageSize = types.Float64Null()
+		}
+		if model.Nfs.Status.IsUnknown() {
+			model.Nfs.Status = types.StringNull()
+		}
+		if model.Nfs.IpAddress.IsUnknown() {
+			model.Nfs.IpAddress = types.StringNull()
+		}
+	}
+}
+
+// isClusterReady reports whether status is the one of a Cluster ready to use.
+func isClusterReady(status string) bool {
+	return strings.EqualFold(status, clusterStatusSuccess) || strings.EqualFold(status, clusterStatusPowerOn)
+}
+
+// clusterStateChangeConf waits for a Cluster to be ready.
+func clusterStateChangeConf(timeout time.Duration, refresh waiter.RefreshFunc) *waiter.StateChangeConf {
+	return &waiter.StateChangeConf{
+		Pending: clusterTransitionalStatuses,
+		Target:  []string{clusterStatusSuccess, clusterStatusPowerOn},
+		Failure: []string{"ERROR", "FAILED"},
+		Refresh: refresh,
+		Timeout: timeout,
+		Delay:   clusterWaitDelay,
+	}
+}
+
+// waitForClusterSettled waits up to timeout for a Cluster to leave the
+// transitional statuses, returning the status it settled in, which may be an
+// error one. The Cluster is first read after delay.
+func waitForClusterSettled(ctx context.Context, apiClient *voksapi.Client, clusterId int32, timeout, delay time.Duration) (string, error) {
+	var status string
+	conf := &waiter.StateChangeConf{
+		Pending: clusterTransitionalStatuses,
+		Target:  []string{clusterStatusSettled},
+		Timeout: timeout,
+		Delay:   delay,
+		Refresh: func(ctx context.Context) (string, error) {
+			cluster, httpRes, err := apiClient.DetailCluster(ctx, clusterId)
+			if err != nil {
+				return "", client.NewAPIError(httpRes, err)
+			}
+			status = cluster.Status
+			if slices.ContainsFunc(clusterTransitionalStatuses, func(transitional string) bool {
+				return strings.EqualFold(status, transitional)
+			}) {
+				return status, nil
+			}
+			return clusterStatusSettled, nil
+		},
+	}
+	_, err := conf.WaitForState(ctx)
+	return status, err
+}
+
+// nfsStateChangeConf waits for the NFS Storage of a Cluster to power on.
+func nfsStateChangeConf(timeout time.Duration, refresh waiter.RefreshFunc) *waiter.StateChangeConf {
+	return &waiter.StateChangeConf{
+		Pending: []string{nfsStatusCreating, nfsStatusUpdating},
+		Target:  []string{nfsStatusPoweredOn},
+		Failure: []string{"ERROR"},
+		Refresh: refresh,
+		Timeout: timeout,
+		Delay:   clusterWaitDelay,
+	}
 }
@@ -5,7 +5,9 @@ package resource
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -16,9 +18,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/viettelidc-provider/viettelidc-api-client-go/service/voks"
-	"github.com/viettelidc-provider/viettelidc-api-client-go/viettelidc"
 	"strconv"
 	"strings"
+	"terraform-provider-viettelidc/internal/client"
+	"terraform-provider-viettelidc/internal/client/voksapi"
+	"terraform-provider-viettelidc/internal/waiter"
 	"time"
 )
 
@@ -26,10 +30,43 @@ var (
 	_ resource.Resource                = &nodeGroupResource{}
 	_ resource.ResourceWithConfigure   = &nodeGroupResource{}
 	_ resource.ResourceWithImportState = &nodeGroupResource{}
+	_ resource.ResourceWithModifyPlan  = &nodeGroupResource{}
 )
 
+const (
+	nodeGroupDefaultCreateTimeout = 30 * time.Minute
+	nodeGroupDefaultUpdateTimeout = 30 * time.Minute
+	nodeGroupDefaultDeleteTimeout = 30 * time.Minute
+
+	nodeGroupStatusCreating = "creating"
+	nodeGroupStatusUpdating = "updating"
+	nodeGroupStatusDeleting = "deleting"
+	nodeGroupStatusSuccess  = "success"
+	// nodeGroupStatusDeleted stands for a node group not found anymore while
+	// waiting for its deletion.
+	nodeGroupStatusDeleted = "deleted"
+
+	// nodeGroupWaitDelay leaves the API time to pick up a change of a node
+	// group before it is first read.
+	nodeGroupWaitDelay = 5 * time.Second
+)
+
+// nodeGroupFieldPaths maps the fields of the node group requests to the
+// attributes they are configured by.
+var nodeGroupFieldPaths = client.FieldPaths{
+	"clusterId":    path.Root("cluster_id"),
+	"name":         path.Root("name"),
+	"resourceType": path.Root("resource_type"),
+	"isAutoRepair": path.Root("auto_repair"),
+	"isAutoScale":  path.Root("scaling_config").AtName("enable_auto_scale"),
+	"minNode":      path.Root("scaling_config").AtName("min_node"),
+	"maxNode":      path.Root("scaling_config").AtName("max_node"),
+	"labels":       path.Root("labels"),
+	"taints":       path.Root("taint"),
+}
+
 type nodeGroupResource struct {
-	client *voks.APIClient
+	client client.ProviderData
 }
 
 func NewNodeGroupResource() resource.Resource {
@@ -46,6 +83,8 @@ type NodeGroupResourceModel struct {
 	Labels        map[string]types.String `tfsdk:"labels"`
 	Taint         []TaintConfigBlock      `tfsdk:"taint"`
 	Status        types.String            `tfsdk:"status"`
+	Region        types.String            `tfsdk:"region"`
+	Timeouts      timeouts.Value          `tfsdk:"timeouts"`
 }
 
 type ScalingConfigBlock struct {
@@ -67,17 +106,17 @@ func (n *nodeGroupResource) Configure(ctx context.Context, request resource.Conf
 		return
 	}
 
-	cfg, ok := request.ProviderData.(*viettelidc.Configuration)
+	providerClient, ok := request.ProviderData.(client.ProviderData)
 	if !ok {
 		response.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *apiclient.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected client.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 
 		return
 	}
 
-	n.client = voks.NewAPIClient(*cfg)
+	n.client = providerClient
 }
 
 func (n *nodeGroupResource) Metadata(ctx context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
@@ -131,6 +170,13 @@ func (n *nodeGroupResource) Schema(ctx context.Context, request resource.SchemaR
 				Description: "The current status of Node Group. Valid values: `CREATING`, `UPDATING`, `SUCCESS`, `ERROR`.",
 				Computed:    true,
 			},
+			"region": schema.StringAttribute{
+				Description: "Region of the resource. Defaults to the region of the provider.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 		},
 		Blocks: map[string]schema.Block{
 			"scaling_config": schema.SingleNestedBlock{
@@ -178,10 +224,24 @@ func (n *nodeGroupResource) Schema(ctx context.Context, request resource.SchemaR
 					},
 				},
 			},
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create:            true,
+				CreateDescription: "How long to wait for the Node Group to be created. Defaults to `30m`.",
+				Update:            true,
+				UpdateDescription: "How long to wait for the Node Group to be updated. Defaults to `30m`.",
+				Delete:            true,
+				DeleteDescription: "How long to wait for the Cluster to settle after deleting the Node Group. Defaults to `30m`.",
+			}),
 		},
 	}
 }
 
+// ModifyPlan defers the change when the provider configuration or the
+// cluster, e.g. created in the same run, is unknown until apply.
+func (n *nodeGroupResource) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	n.client.DeferResourcePlan(ctx, request, response, path.Root("cluster_id"))
+}
+
 func (n *nodeGroupResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
 
 	var plan NodeGroupResourceModel
@@ -191,6 +251,21 @@ func (n *nodeGroupResource) Create(ctx context.Context, request resource.CreateR
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, nodeGroupDefaultCreateTimeout)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	apiClient, diags := n.client.Voks(ctx, plan.Region.ValueString())
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
 	reqBody := voks.CreateNodeGroupRequest{
 		ClusterId:    plan.ClusterId.ValueInt32(),
 		Name:         plan.Name.ValueString(),
@@ -229,36 +304,52 @@ func (n *nodeGroupResource) Create(ctx context.Context, request resource.CreateR
 		})
 	}
 
-	resBody, _, err := n.client.NodeGroupApi.CreateNodeGroup(ctx, reqBody)
+	unlock, diags := lockCluster(ctx, n.client, apiClient, plan.Region.ValueString(), plan.ClusterId.ValueInt32(), createTimeout)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	defer unlock()
+
+	resBody, httpRes, err := apiClient.NodeGroupApi.CreateNodeGroup(ctx, reqBody)
 	if err != nil {
-		response.Diagnostics.AddError(
+		response.Diagnostics.Append(client.ErrorDiagnostics(
 			"Error creating Cluster Node Group",
-			"Could not create Cluster Node Group, unexpected error: "+err.Error())
+			"Could not create Cluster Node Group",
+			httpRes, err, nodeGroupFieldPaths)...)
 		return
 	}
 
-	for {
-		detail, _, err := n.client.NodeGroupApi.DetailNodeGroup(ctx, resBody.ClusterId, resBody.Id)
+	// Save the node group right away, so that Terraform keeps track of it when
+	// the wait below fails or is interrupted
+	plan.ID = types.Int32Value(resBody.Id)
+	plan.Status = types.StringValue(nodeGroupStatusCreating)
+	response.Diagnostics.Append(response.State.Set(ctx, &plan)...)
+	response.Diagnostics.Append(markCreateInProgress(ctx, response.Private)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	conf := nodeGroupStateChangeConf(createTimeout, func(ctx context.Context) (string, error) {
+		detail, httpRes, err := apiClient.NodeGroupApi.DetailNodeGroup(ctx, resBody.ClusterId, resBody.Id)
 		if err != nil {
-			response.Diagnostics.AddError(
-				"Error updating Cluster Node Group status",
-				"Could not update Cluster Node Group status, unexpected error: "+err.Error())
-			return
-		}
-		if detail.Status == "success" {
-			plan.ID = types.Int32Value(detail.Id)
-			plan.Status = types.StringValue(detail.Status)
-			break
-		}
-		if detail.Status == "error" {
-			response.Diagnostics.AddError(
-				"Error creating Cluster Node Group",
-				"Could not create Cluster Node Group.")
-			return
+			return "", client.NewAPIError(httpRes, err)
 		}
-		time.Sleep(10 * time.Second)
+		plan.Status = types.StringValue(detail.Status)
+		return detail.Status, nil
+	})
+	if _, err := conf.WaitForState(ctx); err != nil {
+		response.Diagnostics.Append(client.ErrorDiagnostics(
+			"Error waiting for Cluster Node Group creation",
+			"Could not wait for Cluster Node Group to become ready",
+			nil, err, nil)...)
+		response.Diagnostics.Append(createFailedDiagnostic("Cluster Node Group", strconv.Itoa(int(resBody.Id))))
+		response.Diagnostics.Append(response.State.Set(ctx, &plan)...)
+		return
 	}
 
+	response.Diagnostics.Append(clearCreateInProgress(ctx, response.Private)...)
+
 	// Set state to fully populated data
 	diags = response.State.Set(ctx, &plan)
 	response.Diagnostics.Append(diags...)
@@ -268,6 +359,10 @@ func (n *nodeGroupResource) Create(ctx context.Context, request resource.CreateR
 }
 
 func (n *nodeGroupResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	if n.client.DeferImportState(request, response) {
+		return
+	}
+
 	idParts := strings.Split(request.ID, ",")
 
 	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
@@ -299,6 +394,9 @@ func (n *nodeGroupResource) ImportState(ctx context.Context, request resource.Im
 }
 
 func (n *nodeGroupResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
+	if n.client.DeferResourceRead(request, response) {
+		return
+	}
 
 	var state NodeGroupResourceModel
 	diags := request.State.Get(ctx, &state)
@@ -307,11 +405,22 @@ func (n *nodeGroupResource) Read(ctx context.Context, request resource.ReadReque
 		return
 	}
 
-	detail, _, err := n.client.NodeGroupApi.DetailNodeGroup(ctx, state.ClusterId.ValueInt32(), state.ID.ValueInt32())
+	apiClient, diags := n.client.Voks(ctx, state.Region.ValueString())
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	detail, httpRes, err := apiClient.NodeGroupApi.DetailNodeGroup(ctx, state.ClusterId.ValueInt32(), state.ID.ValueInt32())
+	if client.IsNotFound(httpRes, err) {
+		response.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
-		response.Diagnostics.AddError(
+		response.Diagnostics.Append(client.ErrorDiagnostics(
 			"Error reading Cluster Node Group detail",
-			"Could not read Cluster Addon detail, unexpected error: "+err.Error())
+			"Could not read Node Group detail",
+			httpRes, err, nil)...)
 		return
 	}
 
@@ -325,6 +434,16 @@ func (n *nodeGroupResource) Read(ctx context.Context, request resource.ReadReque
 	}
 	state.Status = types.StringValue(detail.Status)
 
+	// A node group saved before it became ready is either ready by now, or
+	// still tainted and about to be replaced.
+	inProgress, diags := isCreateInProgress(ctx, request.Private)
+	response.Diagnostics.Append(diags...)
+	if inProgress && strings.EqualFold(detail.Status, nodeGroupStatusSuccess) {
+		response.Diagnostics.Append(clearCreateInProgress(ctx, response.Private)...)
+	} else if inProgress {
+		response.Diagnostics.Append(createInProgressDiagnostic("Cluster Node Group", strconv.Itoa(int(state.ID.ValueInt32())), detail.Status))
+	}
+
 	if len(detail.Labels) > 0 {
 		state.Labels = make(map[string]types.String)
 		for _, label := range detail.Labels {
@@ -359,6 +478,21 @@ func (n *nodeGroupResource) Update(ctx context.Context, request resource.UpdateR
 		return
 	}
 
+	updateTimeout, diags := plan.Timeouts.Update(ctx, nodeGroupDefaultUpdateTimeout)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	apiClient, diags := n.client.Voks(ctx, plan.Region.ValueString())
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
 	reqBody := voks.UpdateNodeGroupRequest{
 		ClusterId:    plan.ClusterId.ValueInt32(),
 		Id:           plan.ID.ValueInt32(),
@@ -375,39 +509,41 @@ func (n *nodeGroupResource) Update(ctx context.Context, request resource.UpdateR
 	reqBody.MinNode = plan.ScalingConfig.MinNode.ValueInt32()
 	reqBody.MaxNode = plan.ScalingConfig.MaxNode.ValueInt32()
 
-	updateRes, _, err := n.client.NodeGroupApi.UpdateNodeGroup(ctx, reqBody)
+	unlock, diags := lockCluster(ctx, n.client, apiClient, plan.Region.ValueString(), plan.ClusterId.ValueInt32(), updateTimeout)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	defer unlock()
+
+	updateRes, httpRes, err := apiClient.NodeGroupApi.UpdateNodeGroup(ctx, reqBody)
 	if err != nil {
-		response.Diagnostics.AddError(
+		response.Diagnostics.Append(client.ErrorDiagnostics(
 			"Error updating Cluster Node Group",
-			"Could not update Cluster Node Group, unexpected error: "+err.Error())
+			"Could not update Cluster Node Group",
+			httpRes, err, nodeGroupFieldPaths)...)
 		return
 	}
 
-	for {
-		//refresh status util is success
-		detailRes, _, err := n.client.NodeGroupApi.DetailNodeGroup(ctx, updateRes.ClusterId, updateRes.Id)
+	conf := nodeGroupStateChangeConf(updateTimeout, func(ctx context.Context) (string, error) {
+		detailRes, httpRes, err := apiClient.NodeGroupApi.DetailNodeGroup(ctx, updateRes.ClusterId, updateRes.Id)
 		if err != nil {
-			response.Diagnostics.AddError(
-				"Error updating Cluster Node Group status",
-				"Could not update Cluster Node Group status, unexpected error: "+err.Error())
-			return
-		}
-		if detailRes.Status == "success" {
-			// Update plan with new data
-			plan.AutoRepair = types.BoolValue(detailRes.IsAutoRepair)
-			plan.ScalingConfig.EnableAutoScale = types.BoolValue(detailRes.IsAutoScale)
-			plan.ScalingConfig.MinNode = types.Int32Value(detailRes.MinNode)
-			plan.ScalingConfig.MaxNode = types.Int32Value(detailRes.MaxNode)
-			plan.Status = types.StringValue(detailRes.Status)
-			break
+			return "", client.NewAPIError(httpRes, err)
 		}
-		if detailRes.Status == "error" {
-			response.Diagnostics.AddError(
-				"Error updating Cluster Node Group",
-				"Could not update Cluster Node Group.")
-			return
-		}
-		time.Sleep(10 * time.Second)
+		// Update plan with new data
+		plan.AutoRepair = types.BoolValue(detailRes.IsAutoRepair)
+		plan.ScalingConfig.EnableAutoScale = types.BoolValue(detailRes.IsAutoScale)
+		plan.ScalingConfig.MinNode = types.Int32Value(detailRes.MinNode)
+		plan.ScalingConfig.MaxNode = types.Int32Value(detailRes.MaxNode)
+		plan.Status = types.StringValue(detailRes.Status)
+		return detailRes.Status, nil
+	})
+	if _, err := conf.WaitForState(ctx); err != nil {
+		response.Diagnostics.Append(client.ErrorDiagnostics(
+			"Error waiting for Cluster Node Group update",
+			"Could not wait for Cluster Node Group to become ready",
+			nil, err, nil)...)
+		return
 	}
 
 	// Set state to fully populated data
@@ -427,50 +563,119 @@ func (n *nodeGroupResource) Delete(ctx context.Context, request resource.DeleteR
 		return
 	}
 
-	_, err := n.client.NodeGroupApi.DeleteNodeGroup(ctx, voks.DeleteNodeGroupRequest{
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, nodeGroupDefaultDeleteTimeout)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	apiClient, diags := n.client.Voks(ctx, state.Region.ValueString())
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	unlock, diags := lockClusterForDeletion(ctx, n.client, apiClient, state.Region.ValueString(), state.ClusterId.ValueInt32(), deleteTimeout)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	defer unlock()
+
+	httpRes, err := apiClient.NodeGroupApi.DeleteNodeGroup(ctx, voks.DeleteNodeGroupRequest{
 		ClusterId: state.ClusterId.ValueInt32(),
 		Id:        state.ID.ValueInt32(),
 	})
-
+	if client.IsNotFound(httpRes, err) {
+		// Already deleted outside of Terraform
+		return
+	}
 	if err != nil {
-		response.Diagnostics.AddError(
+		response.Diagnostics.Append(client.ErrorDiagnostics(
 			"Error deleting Cluster Node Group",
-			"Could not delete Cluster Node Group, unexpected error: "+err.Error())
+			"Could not delete Cluster Node Group",
+			httpRes, err, nil)...)
 		return
 	}
 
-	// Check status of cluster
-	for {
-		detailCluster, _, err := n.client.ClusterApi.DetailCluster(ctx, state.ClusterId.ValueInt32())
-		if err != nil {
-			response.Diagnostics.AddError(
-				"Error updating Cluster status",
-				"Could not update Cluster status, unexpected error: "+err.Error())
-			return
-		}
-		if detailCluster.Status == "error" {
-			// Return error response: cluster got err please contact tech support
-			response.Diagnostics.AddError(
-				"Error deleting Cluster Node Group",
-				"Could not delete Cluster Node Group, Cluster got ERROR status, please contact Tech Support.")
-			return
-		}
-		if detailCluster.Status == "success" {
-			break
-		}
-		time.Sleep(10 * time.Second)
+	err = waitForNodeGroupDeleted(ctx, apiClient, state.ClusterId.ValueInt32(), state.ID.ValueInt32(), deleteTimeout)
+	var failureErr *waiter.FailureStateError
+	if errors.As(err, &failureErr) {
+		response.Diagnostics.AddWarning(
+			"Error deleting Cluster Node Group",
+			"Could not delete Cluster Node Group, Node Group got ERROR status, please contact Tech Support.")
+		return
+	}
+	if err != nil {
+		response.Diagnostics.Append(client.ErrorDiagnostics(
+			"Error waiting for Cluster Node Group deletion",
+			"Could not wait for Cluster Node Group to be deleted",
+			nil, err, nil)...)
+		return
 	}
 
-	// Check status of deleted node group
-	detailNodeGroup, _, err := n.client.NodeGroupApi.DetailNodeGroup(ctx, state.ClusterId.ValueInt32(), state.ClusterId.ValueInt32())
+	// Wait for the cluster to settle after removing the node group. A cluster
+	// in error does not keep the node group from being deleted.
+	clusterStatus, err := waitForClusterSettled(ctx, apiClient, state.ClusterId.ValueInt32(), deleteTimeout, clusterWaitDelay)
 	if err != nil {
-		// If node group is not found, it means it was deleted successfully
-	} else {
-		if detailNodeGroup.Status == "error" {
-			response.Diagnostics.AddWarning(
-				"Error deleting Cluster Node Group",
-				"Could not delete Cluster Node Group, Node Group got ERROR status, please contact Tech Support.")
-		}
+		response.Diagnostics.Append(client.ErrorDiagnostics(
+			"Error waiting for Cluster Node Group deletion",
+			"Could not wait for Cluster to settle",
+			nil, err, nil)...)
+		return
+	}
+	if strings.EqualFold(clusterStatus, "error") || strings.EqualFold(clusterStatus, "failed") {
+		response.Diagnostics.AddWarning(
+			"Error deleting Cluster Node Group",
+			"Cluster Node Group was deleted, but Cluster got ERROR status, please contact Tech Support.")
+	}
+}
+
+// waitForNodeGroupDeleted waits up to timeout for a node group to be gone
+// after its deletion was requested. Until the API reports the node group as
+// deleting, it may still report its previous status, so an error status only
+// fails the wait once the deletion has started.
+func waitForNodeGroupDeleted(ctx context.Context, apiClient *voksapi.Client, clusterId, id int32, timeout time.Duration) error {
+	deleting := false
+	conf := &waiter.StateChangeConf{
+		Pending: []string{nodeGroupStatusDeleting, nodeGroupStatusSuccess, "error", "failed"},
+		Target:  []string{nodeGroupStatusDeleted},
+		Timeout: timeout,
+		Delay:   nodeGroupWaitDelay,
+		Refresh: func(ctx context.Context) (string, error) {
+			detail, httpRes, err := apiClient.NodeGroupApi.DetailNodeGroup(ctx, clusterId, id)
+			if client.IsNotFound(httpRes, err) {
+				return nodeGroupStatusDeleted, nil
+			}
+			if err != nil {
+				return "", client.NewAPIError(httpRes, err)
+			}
+			switch {
+			case strings.EqualFold(detail.Status, nodeGroupStatusDeleting):
+				deleting = true
+			case deleting && (strings.EqualFold(detail.Status, "error") || strings.EqualFold(detail.Status, "failed")):
+				return detail.Status, &waiter.FailureStateError{State: detail.Status}
+			}
+			return detail.Status, nil
+		},
+	}
+	_, err := conf.WaitForState(ctx)
+	return err
+}
+
+// nodeGroupStateChangeConf waits up to timeout until refresh reports the
+// success status shared by node groups and clusters.
+func nodeGroupStateChangeConf(timeout time.Duration, refresh waiter.RefreshFunc) *waiter.StateChangeConf {
+	return &waiter.StateChangeConf{
+		Pending: []string{nodeGroupStatusCreating, nodeGroupStatusUpdating},
+		Target:  []string{nodeGroupStatusSuccess},
+		Failure: []string{"error", "failed"},
+		Refresh: refresh,
+		Timeout: timeout,
+		Delay:   nodeGroupWaitDelay,
 	}
 }
 
@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package resource
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// createInProgressKey marks in the private state a resource saved to the state
+// before its creation completed, i.e. before it reached its ready status.
+const createInProgressKey = "create_in_progress"
+
+// privateStateGetter and privateStateSetter are implemented by the private
+// state of the requests and responses.
+type privateStateGetter interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+type privateStateSetter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// markCreateInProgress marks the resource as saved before its creation
+// completed. A create returning an error after saving the resource leaves it
+// tainted, so that it is replaced on the next apply rather than orphaned.
+func markCreateInProgress(ctx context.Context, private privateStateSetter) diag.Diagnostics {
+	return private.SetKey(ctx, createInProgressKey, []byte(`true`))
+}
+
+// clearCreateInProgress marks the creation of the resource as completed.
+func clearCreateInProgress(ctx context.Context, private privateStateSetter) diag.Diagnostics {
+	return private.SetKey(ctx, createInProgressKey, nil)
+}
+
+// isCreateInProgress reports whether the resource was saved before its
+// creation completed.
+func isCreateInProgress(ctx context.Context, private privateStateGetter) (bool, diag.Diagnostics) {
+	value, diags := private.GetKey(ctx, createInProgressKey)
+	return string(value) == `true`, diags
+}
+
+// createFailedDiagnostic explains that a resource saved before its creation
+// completed is kept as tainted.
+func createFailedDiagnostic(resourceName, id string) diag.Diagnostic {
+	return diag.NewWarningDiagnostic(
+		resourceName+" saved as tainted",
+		resourceName+" "+id+" was created but did not become ready. It is kept in the state as tainted, "+
+			"so that the next apply replaces it, or it can be removed with `terraform destroy`.")
+}
+
+// createInProgressDiagnostic explains that a resource saved before its
+// creation completed has still not become ready when it is read again.
+func createInProgressDiagnostic(resourceName, id, status string) diag.Diagnostic {
+	return diag.NewWarningDiagnostic(
+		resourceName+" creation not completed",
+		fmt.Sprintf("%s %s was saved before it became ready and is now in status %s. It is kept in the state as tainted, "+
+			"so that the next apply replaces it, or it can be removed with `terraform destroy`.", resourceName, id, status))
+}
@@ -6,12 +6,12 @@ package datasource
 import (
 	"context"
 	"fmt"
+	"terraform-provider-viettelidc/internal/client"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/viettelidc-provider/viettelidc-api-client-go/service/vpc"
-	"github.com/viettelidc-provider/viettelidc-api-client-go/viettelidc"
 )
 
 var (
@@ -25,10 +25,11 @@ type VpcDatasourceModel struct {
 	Name   types.String `tfsdk:"name"`
 	Status types.String `tfsdk:"status"`
 	Tier   types.String `tfsdk:"tierid"`
+	Region types.String `tfsdk:"region"`
 }
 
 type vpcDatasource struct {
-	client *vpc.APIClient
+	client client.ProviderData
 }
 
 func NewVpcDatasource() datasource.DataSource {
@@ -42,17 +43,17 @@ func (a *vpcDatasource) Configure(ctx context.Context, request datasource.Config
 		return
 	}
 
-	cfg, ok := request.ProviderData.(*viettelidc.Configuration)
+	providerClient, ok := request.ProviderData.(client.ProviderData)
 	if !ok {
 		response.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *apiclient.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected client.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 
 		return
 	}
 
-	a.client = vpc.NewAPIClient(cfg)
+	a.client = providerClient
 }
 
 func (a *vpcDatasource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
@@ -83,11 +84,19 @@ func (a *vpcDatasource) Schema(ctx context.Context, request datasource.SchemaReq
 				Description: "The current status of VPC. Valid values: `Success`, `Suspended`.",
 				Computed:    true,
 			},
+			"region": schema.StringAttribute{
+				Description: "Region to read from. Defaults to the region of the provider.",
+				Optional:    true,
+			},
 		},
 	}
 }
 
 func (a *vpcDatasource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	if a.client.DeferDataSourceRead(request, response) {
+		return
+	}
+
 	var data VpcDatasourceModel
 	diags := request.Config.Get(ctx, &data)
 	response.Diagnostics.Append(diags...)
@@ -95,13 +104,19 @@ func (a *vpcDatasource) Read(ctx context.Context, request datasource.ReadRequest
 		return
 	}
 
+	apiClient, diags := a.client.Vpc(ctx, data.Region.ValueString())
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
 	reqBody := vpc.VpcGetItemRequest{
 		Id:    data.ID.ValueInt32(),
 		VpcId: data.VpcId.ValueInt32(),
 	}
-	result, httpResp, err := a.client.VirtualPrivateCloudApi.VpcGetDetail(ctx, reqBody)
+	result, httpResp, err := apiClient.VirtualPrivateCloudApi.VpcGetDetail(client.WithRetrySafe(ctx), reqBody)
 	if err != nil {
-		response.Diagnostics.AddError("Error calling API", err.Error())
+		response.Diagnostics.Append(client.ErrorDiagnostics("Error calling API", "Could not read VPC detail", httpResp, err, nil)...)
 		return
 	}
 	if httpResp != nil && httpResp.Body != nil {
@@ -111,7 +126,8 @@ func (a *vpcDatasource) Read(ctx context.Context, request datasource.ReadRequest
 	var state VpcDatasourceModel
 	// Copy the input parameters and set the response data
 	state.VpcId = data.VpcId
-	state.ID = data.ID  // Keep the input ID
+	state.ID = data.ID // Keep the input ID
+	state.Region = data.Region
 	state.Name = types.StringValue(result.Name)
 	state.Status = types.StringValue(result.Status)
 	state.Tier = types.StringValue(fmt.Sprint(result.TierId)) // convert int32 → string
@@ -6,12 +6,12 @@ package datasource
 import (
 	"context"
 	"fmt"
+	"terraform-provider-viettelidc/internal/client"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/viettelidc-provider/viettelidc-api-client-go/service/vpc"
-	"github.com/viettelidc-provider/viettelidc-api-client-go/viettelidc"
 )
 
 var (
@@ -20,12 +20,13 @@ var (
 )
 
 type vpcQuotaLimitDatasource struct {
-	client *vpc.APIClient
+	client client.ProviderData
 }
 
 type VpcQuotaLimitDatasourceModel struct {
-	VpcId types.Int32  `tfsdk:"vpc_id"`
-	Items []QuotaModel `tfsdk:"items"`
+	VpcId  types.Int32  `tfsdk:"vpc_id"`
+	Items  []QuotaModel `tfsdk:"items"`
+	Region types.String `tfsdk:"region"`
 }
 type QuotaModel struct {
 	Name  types.String `tfsdk:"name"`
@@ -45,17 +46,17 @@ func (a *vpcQuotaLimitDatasource) Configure(ctx context.Context, request datasou
 		return
 	}
 
-	cfg, ok := request.ProviderData.(*viettelidc.Configuration)
+	providerClient, ok := request.ProviderData.(client.ProviderData)
 	if !ok {
 		response.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *apiclient.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected client.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 
 		return
 	}
 
-	a.client = vpc.NewAPIClient(cfg)
+	a.client = providerClient
 }
 
 func (a *vpcQuotaLimitDatasource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
@@ -94,11 +95,19 @@ func (a *vpcQuotaLimitDatasource) Schema(ctx context.Context, request datasource
 					},
 				},
 			},
+			"region": schema.StringAttribute{
+				Description: "Region to read from. Defaults to the region of the provider.",
+				Optional:    true,
+			},
 		},
 	}
 }
 
 func (a *vpcQuotaLimitDatasource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	if a.client.DeferDataSourceRead(request, response) {
+		return
+	}
+
 	var data VpcQuotaLimitDatasourceModel
 	diags := request.Config.Get(ctx, &data)
 	response.Diagnostics.Append(diags...)
@@ -106,13 +115,19 @@ func (a *vpcQuotaLimitDatasource) Read(ctx context.Context, request datasource.R
 		return
 	}
 
+	apiClient, diags := a.client.Vpc(ctx, data.Region.ValueString())
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
 	reqBody := vpc.VpcGetQuotaRequest{
 		VpcId: data.VpcId.ValueInt32(),
 	}
 
-	result, httpResp, err := a.client.VirtualPrivateCloudApi.VpcGetQuotaLimit(ctx, reqBody)
+	result, httpResp, err := apiClient.VirtualPrivateCloudApi.VpcGetQuotaLimit(client.WithRetrySafe(ctx), reqBody)
 	if err != nil {
-		response.Diagnostics.AddError("Error calling API", fmt.Sprintf("API Error: %v", err))
+		response.Diagnostics.Append(client.ErrorDiagnostics("Error calling API", "Could not read VPC quota limits", httpResp, err, nil)...)
 		return
 	}
 	if httpResp != nil && httpResp.Body != nil {
@@ -121,6 +136,7 @@ func (a *vpcQuotaLimitDatasource) Read(ctx context.Context, request datasource.R
 
 	var state VpcQuotaLimitDatasourceModel
 	state.VpcId = data.VpcId
+	state.Region = data.Region
 
 	// Process the API response
 	for _, item := range result.Items {
@@ -6,12 +6,13 @@ package datasource
 import (
 	"context"
 	"fmt"
+	"terraform-provider-viettelidc/internal/client"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/viettelidc-provider/viettelidc-api-client-go/service/vpc"
-	"github.com/viettelidc-provider/viettelidc-api-client-go/viettelidc"
 )
 
 var (
@@ -20,13 +21,14 @@ var (
 )
 
 type vpcsDatasource struct {
-	client *vpc.APIClient
+	client client.ProviderData
 }
 
 type VpcsDatasourceModel struct {
-	VpcId  types.Int32 `tfsdk:"vpc_id"`
-	HostId types.Int32 `tfsdk:"host_id"`
-	Items  []VpcModel  `tfsdk:"items"`
+	VpcId  types.Int32  `tfsdk:"vpc_id"`
+	HostId types.Int32  `tfsdk:"host_id"`
+	Items  []VpcModel   `tfsdk:"items"`
+	Region types.String `tfsdk:"region"`
 }
 type VpcModel struct {
 	ID     types.Int32  `tfsdk:"id"`
@@ -46,17 +48,17 @@ func (a *vpcsDatasource) Configure(ctx context.Context, request datasource.Confi
 		return
 	}
 
-	cfg, ok := request.ProviderData.(*viettelidc.Configuration)
+	providerClient, ok := request.ProviderData.(client.ProviderData)
 	if !ok {
 		response.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *apiclient.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+			fmt.Sprintf("Expected client.ProviderData, got: %T. Please report this issue to the provider developers.", request.ProviderData),
 		)
 
 		return
 	}
 
-	a.client = vpc.NewAPIClient(cfg)
+	a.client = providerClient
 }
 
 func (a *vpcsDatasource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
@@ -72,8 +74,8 @@ func (a *vpcsDatasource) Schema(ctx context.Context, request datasource.SchemaRe
 				Required:    true,
 			},
 			"host_id": schema.Int32Attribute{
-				Description: "Host ID to filter the results.",
-				Required:    true,
+				Description: "Host ID to filter the results. Defaults to the `host_id` of the region declared in the `regions` of the provider.",
+				Optional:    true,
 			},
 			"items": schema.ListNestedAttribute{
 				Computed:    true,
@@ -99,11 +101,19 @@ func (a *vpcsDatasource) Schema(ctx context.Context, request datasource.SchemaRe
 					},
 				},
 			},
+			"region": schema.StringAttribute{
+				Description: "Region to read from. Defaults to the region of the provider.",
+				Optional:    true,
+			},
 		},
 	}
 }
 
 func (a *vpcsDatasource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	if a.client.DeferDataSourceRead(request, response) {
+		return
+	}
+
 	var data VpcsDatasourceModel
 	diags := request.Config.Get(ctx, &data)
 	response.Diagnostics.Append(diags...)
@@ -111,14 +121,49 @@ func (a *vpcsDatasource) Read(ctx context.Context, request datasource.ReadReques
 		return
 	}
 
+	apiClient, diags := a.client.Vpc(ctx, data.Region.ValueString())
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
 	reqBody := vpc.VpcGetListRequest{
 		HostId: data.HostId.ValueInt32(),
 		VpcId:  data.VpcId.ValueInt32(),
 	}
 
-	result, httpResp, err := a.client.VirtualPrivateCloudApi.VpcGetList(ctx, reqBody)
+	if data.HostId.IsNull() {
+		regionCode := data.Region.ValueString()
+		if regionCode == "" {
+			regionCode = a.client.Region()
+		}
+		if regionCode == "" {
+			response.Diagnostics.AddAttributeError(
+				path.Root("host_id"),
+				"Missing Host ID",
+				"Set `host_id`, or a `region` on the data source or the provider to list the VPCs of its host.",
+			)
+			return
+		}
+		region, err := client.LookupRegion(a.client.Regions(), regionCode)
+		if err != nil {
+			response.Diagnostics.AddAttributeError(path.Root("region"), "Invalid Viettelidc Region", err.Error())
+			return
+		}
+		if region.HostId == nil {
+			response.Diagnostics.AddAttributeError(
+				path.Root("host_id"),
+				"Missing Host ID",
+				fmt.Sprintf("Set `host_id`, or the `host_id` of the region %q in the `regions` of the provider to list the VPCs of its host.", region.Code),
+			)
+			return
+		}
+		reqBody.HostId = *region.HostId
+	}
+
+	result, httpResp, err := apiClient.VirtualPrivateCloudApi.VpcGetList(client.WithRetrySafe(ctx), reqBody)
 	if err != nil {
-		response.Diagnostics.AddError("Error calling API", err.Error())
+		response.Diagnostics.Append(client.ErrorDiagnostics("Error calling API", "Could not read VPCs", httpResp, err, nil)...)
 		return
 	}
 	if httpResp != nil && httpResp.Body != nil {
@@ -129,6 +174,7 @@ func (a *vpcsDatasource) Read(ctx context.Context, request datasource.ReadReques
 	// Copy the input parameters to the state
 	state.VpcId = data.VpcId
 	state.HostId = data.HostId
+	state.Region = data.Region
 	for _, item := range result.Items {
 		state.Items = append(state.Items, VpcModel{
 			ID:     types.Int32Value(item.Id),
@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fakeapi
+
+import (
+	"net/http"
+)
+
+type loginRequest struct {
+	Username     string `json:"username"`
+	Password     string `json:"password"`
+	DomainId     string `json:"domainId"`
+	IsRememberMe bool   `json:"isRememberMe"`
+	UserType     string `json:"userType"`
+}
+
+type loginResponse struct {
+	Data                               string `json:"data"`
+	IsRequiredSecondAuthenticationStep bool   `json:"isRequiredSecondAuthenticationStep"`
+}
+
+type verifyMfaRequest struct {
+	MfaToken string `json:"mfaToken"`
+	MfaCode  string `json:"mfaCode"`
+}
+
+type dataResponse[T any] struct {
+	Data T `json:"data"`
+}
+
+func (s *Server) login(r *http.Request) (any, error) {
+	var body loginRequest
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	if body.DomainId != s.DomainId || body.Username != s.Username || body.Password != s.Password {
+		return nil, errorf(http.StatusUnauthorized, "INVALID_CREDENTIALS", "Invalid domain, username or password")
+	}
+
+	if s.MfaCode != "" {
+		mfaToken := newToken()
+		s.mfaTokens[mfaToken] = true
+		return loginResponse{Data: mfaToken, IsRequiredSecondAuthenticationStep: true}, nil
+	}
+
+	token := newToken()
+	s.tokens[token] = true
+	return loginResponse{Data: token}, nil
+}
+
+func (s *Server) verifyMfa(r *http.Request) (any, error) {
+	var body verifyMfaRequest
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	if !s.mfaTokens[body.MfaToken] {
+		return nil, errorf(http.StatusUnauthorized, "INVALID_MFA_TOKEN", "MFA token is invalid or expired")
+	}
+	if body.MfaCode != s.MfaCode {
+		return nil, errorf(http.StatusUnauthorized, "INVALID_MFA_CODE", "Invalid MFA code")
+	}
+
+	delete(s.mfaTokens, body.MfaToken)
+	token := newToken()
+	s.tokens[token] = true
+	return dataResponse[string]{Data: token}, nil
+}
+
+func (s *Server) accountInfo(r *http.Request) (any, error) {
+	return dataResponse[Account]{Data: s.Account}, nil
+}
@@ -0,0 +1,350 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fakeapi
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Account is the account of the logged in user.
+type Account struct {
+	Id         string `json:"id"`
+	DomainId   string `json:"domainId"`
+	CustomerId string `json:"customerId"`
+}
+
+// VpcConfig is the network of a Cluster.
+type VpcConfig struct {
+	VpcId            int32   `json:"vpcId"`
+	SecurityGroupIds []int32 `json:"securityGroupIds,omitempty"`
+	SubnetIds        []int32 `json:"subnetIds,omitempty"`
+}
+
+// Cluster is a vOKS cluster.
+type Cluster struct {
+	Id                       int32     `json:"id"`
+	Name                     string    `json:"name"`
+	Status                   string    `json:"status"`
+	Version                  string    `json:"version"`
+	ApiAddress               string    `json:"apiAddress"`
+	ControlPlaneResourceType string    `json:"controlPlaneResourceType"`
+	VpcConfig                VpcConfig `json:"vpcConfig"`
+
+	// Nfs is the NFS storage of the cluster, if any.
+	Nfs *Nfs `json:"-"`
+	// Kubeconfig is returned by the kubeconfig endpoint. A kubeconfig
+	// pointing to ApiAddress is generated when empty.
+	Kubeconfig string `json:"-"`
+}
+
+// Nfs is the NFS storage of a Cluster.
+type Nfs struct {
+	CpuSize     float64 `json:"cpuSize"`
+	MemorySize  float64 `json:"memorySize"`
+	StorageSize float64 `json:"storageSize"`
+	Status      string  `json:"status"`
+	InternalIp  string  `json:"internalIp"`
+}
+
+// Label is a Kubernetes label of a NodeGroup.
+type Label struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Taint is a Kubernetes taint of a NodeGroup.
+type Taint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Effect string `json:"effect"`
+}
+
+// NodeGroup is a node group of a Cluster.
+type NodeGroup struct {
+	Id           int32   `json:"id"`
+	ClusterId    int32   `json:"clusterId"`
+	Name         string  `json:"name"`
+	ResourceType string  `json:"resourceType"`
+	Status       string  `json:"status"`
+	IsAutoRepair bool    `json:"isAutoRepair"`
+	IsAutoScale  bool    `json:"isAutoScale"`
+	MinNode      int32   `json:"minNode"`
+	MaxNode      int32   `json:"maxNode"`
+	Labels       []Label `json:"labels"`
+	Taints       []Taint `json:"taints"`
+}
+
+// Addon is an addon of the catalog, installable on the clusters running
+// KubernetesVersion.
+type Addon struct {
+	Name              string
+	KubernetesVersion string
+	// Versions are listed newest first.
+	Versions []string
+}
+
+// Vpc is a virtual private cloud.
+type Vpc struct {
+	Id     int32  `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	TierId int32  `json:"tierId"`
+}
+
+// Quota is a resource limit of a Vpc.
+type Quota struct {
+	Name  string `json:"name"`
+	Unit  string `json:"unit"`
+	Value int64  `json:"value"`
+	Usage int64  `json:"usage"`
+}
+
+// Statuses reported by the fake, mirroring the ones of the API.
+const (
+	ClusterStatusCreating  = "CREATING"
+	ClusterStatusSuccess   = "SUCCESS"
+	ClusterStatusUpdating  = "UPDATING"
+	ClusterStatusUpgrading = "UPGRADING"
+	ClusterStatusDeleting  = "DELETING"
+	ClusterStatusError     = "ERROR"
+
+	NfsStatusPoweredOn = "POWERED_ON"
+	NfsStatusUpdating  = "UPDATING"
+	NfsStatusDeleting  = "DELETING"
+
+	NodeGroupStatusCreating = "creating"
+	NodeGroupStatusUpdating = "updating"
+	NodeGroupStatusDeleting = "deleting"
+	NodeGroupStatusSuccess  = "success"
+
+	AddonStatusInstalling   = "installing"
+	AddonStatusUninstalling = "uninstalling"
+	AddonStatusActive       = "active"
+	AddonStatusInactive     = "inactive"
+)
+
+// statusDeleted is the settled status of an object being deleted. The object
+// is removed by the read settling it, which reports it as not found.
+const statusDeleted = "DELETED"
+
+// status is the status of an object, moving from a transitional status to
+// its settled status after a number of reads.
+type status struct {
+	current string
+	settled string
+	reads   int
+	// next is the transitional status of a queued transition, reported
+	// from the read after the next one.
+	next string
+}
+
+func newStatus(current string) status {
+	return status{current: current}
+}
+
+func (st *status) transition(transitional, settled string, reads int) {
+	st.current = transitional
+	st.settled = settled
+	st.reads = reads
+	st.next = ""
+}
+
+// queue is transition, except that the current status is read once more
+// before the transitional one, as the API does for the operations it queues
+// before starting them.
+func (st *status) queue(transitional, settled string, reads int) {
+	st.next = transitional
+	st.settled = settled
+	st.reads = reads
+}
+
+// pending reports whether the object has not settled yet.
+func (st *status) pending() bool {
+	return st.settled != ""
+}
+
+// read returns the status reported by a read of the object.
+func (st *status) read() string {
+	if st.next != "" {
+		current := st.current
+		st.current = st.next
+		st.next = ""
+		return current
+	}
+	if st.settled != "" {
+		if st.reads <= 0 {
+			st.current = st.settled
+			st.settled = ""
+		} else {
+			st.reads--
+		}
+	}
+	return st.current
+}
+
+type cluster struct {
+	Cluster
+	status status
+	// upgradeVersion is the version the cluster is being upgraded to, which
+	// it reports once the upgrade completes.
+	upgradeVersion string
+
+	nfsStatus       status
+	nfsBaseStorage  float64
+	nfsExtraStorage int32
+}
+
+type nodeGroup struct {
+	NodeGroup
+	status status
+}
+
+type installedAddon struct {
+	version string
+	status  status
+}
+
+// AddCluster adds a cluster, with its NFS storage when Nfs is set. The id
+// is allocated when zero. It returns the id of the cluster.
+func (s *Server) AddCluster(c Cluster) int32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c.Id == 0 {
+		c.Id = s.id()
+	}
+	if c.Status == "" {
+		c.Status = ClusterStatusSuccess
+	}
+	stored := &cluster{
+		Cluster: c,
+		status:  newStatus(c.Status),
+	}
+	if c.Nfs != nil {
+		nfs := *c.Nfs
+		if nfs.Status == "" {
+			nfs.Status = NfsStatusPoweredOn
+		}
+		stored.Nfs = &nfs
+		stored.nfsStatus = newStatus(nfs.Status)
+		stored.nfsBaseStorage = nfs.StorageSize
+	}
+	s.clusters[c.Id] = stored
+	return c.Id
+}
+
+// AddNodeGroup adds a node group to an existing cluster. The id is
+// allocated when zero. It returns the id of the node group.
+func (s *Server) AddNodeGroup(ng NodeGroup) int32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.clusters[ng.ClusterId]; !ok {
+		panic(fmt.Sprintf("fakeapi: cluster %d does not exist", ng.ClusterId))
+	}
+	if ng.Id == 0 {
+		ng.Id = s.id()
+	}
+	if ng.Status == "" {
+		ng.Status = NodeGroupStatusSuccess
+	}
+	s.nodeGroups[ng.Id] = &nodeGroup{
+		NodeGroup: ng,
+		status:    newStatus(ng.Status),
+	}
+	return ng.Id
+}
+
+// AddAddon adds an addon to the catalog.
+func (s *Server) AddAddon(addon Addon) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.catalog = append(s.catalog, addon)
+}
+
+// InstallAddon installs an addon of the catalog on an existing cluster,
+// without going through the installing status.
+func (s *Server) InstallAddon(clusterId int32, name, version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.clusters[clusterId]; !ok {
+		panic(fmt.Sprintf("fakeapi: cluster %d does not exist", clusterId))
+	}
+	if s.addons[clusterId] == nil {
+		s.addons[clusterId] = map[string]*installedAddon{}
+	}
+	s.addons[clusterId][name] = &installedAddon{
+		version: version,
+		status:  newStatus(AddonStatusActive),
+	}
+}
+
+// AddVpc adds a VPC with its quotas. The id is allocated when zero. It
+// returns the id of the VPC.
+func (s *Server) AddVpc(vpc Vpc, quotas ...Quota) int32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if vpc.Id == 0 {
+		vpc.Id = s.id()
+	}
+	s.vpcs[vpc.Id] = &vpc
+	s.quotas[vpc.Id] = slices.Clone(quotas)
+	return vpc.Id
+}
+
+// Cluster returns the cluster as last read through the API.
+func (s *Server) Cluster(id int32) (Cluster, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.clusters[id]
+	if !ok {
+		return Cluster{}, false
+	}
+	copied := c.Cluster
+	copied.Status = c.status.current
+	return copied, true
+}
+
+// SetClusterStatus sets the status of an existing cluster, e.g. to put it in
+// error.
+func (s *Server) SetClusterStatus(id int32, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.clusters[id]
+	if !ok {
+		panic(fmt.Sprintf("fakeapi: cluster %d does not exist", id))
+	}
+	c.status = newStatus(status)
+}
+
+// NodeGroup returns the node group as last read through the API.
+func (s *Server) NodeGroup(id int32) (NodeGroup, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ng, ok := s.nodeGroups[id]
+	if !ok {
+		return NodeGroup{}, false
+	}
+	copied := ng.NodeGroup
+	copied.Status = ng.status.current
+	return copied, true
+}
+
+// addon returns the catalog entry of the addon for the Kubernetes version,
+// or of any version when kubernetesVersion is empty.
+func (s *Server) addon(name, kubernetesVersion string) (Addon, bool) {
+	for _, addon := range s.catalog {
+		if addon.Name == name && (kubernetesVersion == "" || addon.KubernetesVersion == kubernetesVersion) {
+			return addon, true
+		}
+	}
+	return Addon{}, false
+}
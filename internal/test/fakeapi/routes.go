@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fakeapi
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// routes maps the operations of the API clients to the paths they call. The
+// operations are named after the API client methods.
+var routes = []route{
+	// IAM
+	{"LoginViaLoginPage", http.MethodPost, "/iam/v1/auth/login", (*Server).login, true},
+	{"VerifyMfaTokenCode", http.MethodPost, "/iam/v1/auth/mfa/verify", (*Server).verifyMfa, true},
+	{"GetAccountInfoClient", http.MethodGet, "/iam/v1/account/info", (*Server).accountInfo, false},
+
+	// vOKS
+	{"CreateCluster", http.MethodPost, "/voks/v1/clusters", (*Server).createCluster, false},
+	{"DeleteCluster", http.MethodDelete, "/voks/v1/clusters", (*Server).deleteCluster, false},
+	{"UpgradeCluster", http.MethodPost, "/voks/v1/clusters/upgrade", (*Server).upgradeCluster, false},
+	{"DetailCluster", http.MethodGet, "/voks/v1/clusters/{id}", (*Server).detailCluster, false},
+	{"KubeConfigCluster", http.MethodPost, "/voks/v1/clusters/kubeconfig", (*Server).kubeconfig, false},
+	{"DetailNfsStorage", http.MethodPost, "/voks/v1/nfs/detail", (*Server).detailNfs, false},
+	{"ExtendNfsStorage", http.MethodPost, "/voks/v1/nfs/extend", (*Server).extendNfs, false},
+	{"DeleteNfsStorage", http.MethodPost, "/voks/v1/nfs/delete", (*Server).deleteNfs, false},
+	{"CreateNodeGroup", http.MethodPost, "/voks/v1/node-groups", (*Server).createNodeGroup, false},
+	{"UpdateNodeGroup", http.MethodPut, "/voks/v1/node-groups", (*Server).updateNodeGroup, false},
+	{"DeleteNodeGroup", http.MethodDelete, "/voks/v1/node-groups", (*Server).deleteNodeGroup, false},
+	{"UpgradeNodeGroup", http.MethodPost, "/voks/v1/node-groups/upgrade", (*Server).upgradeNodeGroup, false},
+	{"GetAllNodeGroup", http.MethodGet, "/voks/v1/clusters/{clusterId}/node-groups", (*Server).listNodeGroups, false},
+	{"DetailNodeGroup", http.MethodGet, "/voks/v1/clusters/{clusterId}/node-groups/{id}", (*Server).detailNodeGroup, false},
+	{"GetAllAddOn", http.MethodGet, "/voks/v1/addons", (*Server).listAddons, false},
+	{"GetAllAddonVersion", http.MethodGet, "/voks/v1/addons/{name}/versions", (*Server).listAddonVersions, false},
+	{"GetAllInstalledAddon", http.MethodGet, "/voks/v1/clusters/{clusterId}/addons", (*Server).listInstalledAddons, false},
+	{"GetDetailAddon", http.MethodGet, "/voks/v1/clusters/{clusterId}/addons/{name}", (*Server).detailAddon, false},
+	{"InstallAddOn", http.MethodPost, "/voks/v1/addons/install", (*Server).installAddon, false},
+	{"UninstallAddOn", http.MethodPost, "/voks/v1/addons/uninstall", (*Server).uninstallAddon, false},
+
+	// VPC
+	{"VpcGetList", http.MethodPost, "/vpc/v1/vpcs/list", (*Server).listVpcs, false},
+	{"VpcGetDetail", http.MethodPost, "/vpc/v1/vpcs/detail", (*Server).detailVpc, false},
+	{"VpcGetQuotaLimit", http.MethodPost, "/vpc/v1/vpcs/quota-limit", (*Server).vpcQuotaLimit, false},
+}
+
+// pathId returns the int32 path parameter name.
+func pathId(r *http.Request, name string) (int32, error) {
+	id, err := strconv.ParseInt(r.PathValue(name), 10, 32)
+	if err != nil {
+		return 0, errorf(http.StatusBadRequest, "INVALID_PARAMETER", "Invalid %s %q", name, r.PathValue(name))
+	}
+	return int32(id), nil
+}
@@ -0,0 +1,267 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package fakeapi is an in-memory fake of the Viettelidc IAM, vOKS and VPC
+// APIs for running the acceptance tests without a cloud account. The provider
+// targets it through its `host` argument.
+package fakeapi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultDomainId, DefaultUsername and DefaultPassword are the
+	// credentials accepted by a new Server.
+	DefaultDomainId = "00000000-0000-0000-0000-000000000001"
+	DefaultUsername = "terraform"
+	DefaultPassword = "terraform"
+
+	// DefaultTransitionReads is the number of reads reporting a transitional
+	// status, such as "creating", before the object settles.
+	DefaultTransitionReads = 1
+)
+
+// Server is a fake Viettelidc API gateway serving every service under one
+// base URL.
+type Server struct {
+	*httptest.Server
+
+	mu sync.Mutex
+
+	// DomainId, Username and Password are the credentials accepted by the
+	// login endpoint.
+	DomainId string
+	Username string
+	Password string
+	// MfaCode, when set, makes the login require a second authentication
+	// step verified against this code.
+	MfaCode string
+	// Account is returned by the account info endpoint.
+	Account Account
+	// TransitionReads is the number of reads reporting a transitional status
+	// before the object settles.
+	TransitionReads int
+
+	tokens    map[string]bool
+	mfaTokens map[string]bool
+
+	clusters   map[int32]*cluster
+	nodeGroups map[int32]*nodeGroup
+	addons     map[int32]map[string]*installedAddon
+	catalog    []Addon
+	vpcs       map[int32]*Vpc
+	quotas     map[int32][]Quota
+	nextId     int32
+
+	calls    map[string]int
+	failures map[string][]injectedError
+}
+
+// NewServer starts a Server without any object. The caller must Close it.
+func NewServer() *Server {
+	s := &Server{
+		DomainId: DefaultDomainId,
+		Username: DefaultUsername,
+		Password: DefaultPassword,
+		Account: Account{
+			Id:         "00000000-0000-0000-0000-000000000002",
+			DomainId:   DefaultDomainId,
+			CustomerId: "00000000-0000-0000-0000-000000000003",
+		},
+		TransitionReads: DefaultTransitionReads,
+
+		tokens:     map[string]bool{},
+		mfaTokens:  map[string]bool{},
+		clusters:   map[int32]*cluster{},
+		nodeGroups: map[int32]*nodeGroup{},
+		addons:     map[int32]map[string]*installedAddon{},
+		vpcs:       map[int32]*Vpc{},
+		quotas:     map[int32][]Quota{},
+		nextId:     1000,
+		calls:      map[string]int{},
+		failures:   map[string][]injectedError{},
+	}
+
+	mux := http.NewServeMux()
+	for _, r := range routes {
+		mux.HandleFunc(r.method+" "+r.path, s.handle(r))
+	}
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// ProviderConfig returns a provider block targeting the server with its
+// credentials.
+func (s *Server) ProviderConfig() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return fmt.Sprintf(`
+provider "viettelidc" {
+  host      = %q
+  domain_id = %q
+  username  = %q
+  password  = %q
+}
+`, s.URL, s.DomainId, s.Username, s.Password)
+}
+
+// Calls returns how many times the operation, named after the API client
+// method such as "CreateNodeGroup", has been called.
+func (s *Server) Calls(operation string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.calls[operation]
+}
+
+// FailNext makes the next call of the operation fail with the status code
+// and an error body carrying code and message.
+func (s *Server) FailNext(operation string, statusCode int, code, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[operation] = append(s.failures[operation], injectedError{
+		statusCode: statusCode,
+		code:       code,
+		message:    message,
+	})
+}
+
+// ExpireTokens revokes every access token issued so far, so the next call
+// is rejected and the provider has to log in again.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.tokens)
+}
+
+type injectedError struct {
+	statusCode int
+	code       string
+	message    string
+}
+
+// handlerFunc handles a request with the server lock held. It returns the
+// response body, or an *apiError.
+type handlerFunc func(s *Server, r *http.Request) (any, error)
+
+type route struct {
+	operation string
+	method    string
+	path      string
+	handler   handlerFunc
+	// public routes are served without an access token.
+	public bool
+}
+
+func (s *Server) handle(rt route) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", newToken())
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.calls[rt.operation]++
+
+		if failures := s.failures[rt.operation]; len(failures) > 0 {
+			s.failures[rt.operation] = failures[1:]
+			writeError(w, &apiError{
+				statusCode: failures[0].statusCode,
+				Code:       failures[0].code,
+				Message:    failures[0].message,
+			})
+			return
+		}
+
+		if !rt.public && !s.tokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")] {
+			writeError(w, errorf(http.StatusUnauthorized, "UNAUTHORIZED", "Access token is missing or expired"))
+			return
+		}
+
+		body, err := rt.handler(s, r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if body == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
+
+// apiError is written as a JSON error body.
+type apiError struct {
+	statusCode int
+	Code       string       `json:"code,omitempty"`
+	Message    string       `json:"message"`
+	Errors     []fieldError `json:"errors,omitempty"`
+}
+
+type fieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *apiError) Error() string {
+	return e.Message
+}
+
+func errorf(statusCode int, code, format string, args ...any) *apiError {
+	return &apiError{
+		statusCode: statusCode,
+		Code:       code,
+		Message:    fmt.Sprintf(format, args...),
+	}
+}
+
+func invalidField(field, message string) *apiError {
+	return &apiError{
+		statusCode: http.StatusBadRequest,
+		Code:       "VALIDATION_FAILED",
+		Message:    "Validation failed",
+		Errors:     []fieldError{{Field: field, Message: message}},
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	apiErr, ok := err.(*apiError)
+	if !ok {
+		apiErr = errorf(http.StatusInternalServerError, "INTERNAL_ERROR", "%s", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(apiErr.statusCode)
+	_ = json.NewEncoder(w).Encode(apiErr)
+}
+
+func decode(r *http.Request, v any) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return errorf(http.StatusBadRequest, "INVALID_BODY", "Could not decode request body: %s", err)
+	}
+	return nil
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// id allocates the id of a new object.
+func (s *Server) id() int32 {
+	s.nextId++
+	return s.nextId
+}
@@ -0,0 +1,434 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fakeapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// call sends body as JSON and decodes the JSON response into out.
+func call(t *testing.T, s *Server, token, method, path string, body, out any) int {
+	t.Helper()
+
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req, err := http.NewRequest(method, s.URL+path, &reqBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res, err := s.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	if res.Header.Get("X-Request-Id") == "" {
+		t.Errorf("expected a request id on %s %s", method, path)
+	}
+	if out != nil && res.StatusCode < 300 {
+		if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+			t.Fatalf("decoding %s %s: %s", method, path, err)
+		}
+	}
+	return res.StatusCode
+}
+
+func login(t *testing.T, s *Server) string {
+	t.Helper()
+
+	var res loginResponse
+	status := call(t, s, "", http.MethodPost, "/iam/v1/auth/login", loginRequest{
+		Username: s.Username,
+		Password: s.Password,
+		DomainId: s.DomainId,
+	}, &res)
+	if status != http.StatusOK || res.IsRequiredSecondAuthenticationStep {
+		t.Fatalf("expected login to succeed, got %d %+v", status, res)
+	}
+	return res.Data
+}
+
+func TestServer_Login(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.MfaCode = "123456"
+
+	if status := call(t, s, "", http.MethodPost, "/iam/v1/auth/login", loginRequest{
+		Username: s.Username,
+		Password: "wrong",
+		DomainId: s.DomainId,
+	}, nil); status != http.StatusUnauthorized {
+		t.Errorf("expected invalid password to be rejected, got %d", status)
+	}
+
+	var loginRes loginResponse
+	call(t, s, "", http.MethodPost, "/iam/v1/auth/login", loginRequest{
+		Username: s.Username,
+		Password: s.Password,
+		DomainId: s.DomainId,
+	}, &loginRes)
+	if !loginRes.IsRequiredSecondAuthenticationStep {
+		t.Fatal("expected a second authentication step")
+	}
+
+	if status := call(t, s, "", http.MethodPost, "/iam/v1/auth/mfa/verify", verifyMfaRequest{
+		MfaToken: loginRes.Data,
+		MfaCode:  "000000",
+	}, nil); status != http.StatusUnauthorized {
+		t.Errorf("expected invalid MFA code to be rejected, got %d", status)
+	}
+
+	var tokenRes dataResponse[string]
+	call(t, s, "", http.MethodPost, "/iam/v1/auth/mfa/verify", verifyMfaRequest{
+		MfaToken: loginRes.Data,
+		MfaCode:  s.MfaCode,
+	}, &tokenRes)
+
+	var accountRes dataResponse[Account]
+	if status := call(t, s, tokenRes.Data, http.MethodGet, "/iam/v1/account/info", nil, &accountRes); status != http.StatusOK {
+		t.Fatalf("expected account info with the exchanged token, got %d", status)
+	}
+	if accountRes.Data != s.Account {
+		t.Errorf("expected account %+v, got %+v", s.Account, accountRes.Data)
+	}
+
+	s.ExpireTokens()
+	if status := call(t, s, tokenRes.Data, http.MethodGet, "/iam/v1/account/info", nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("expected expired token to be rejected, got %d", status)
+	}
+}
+
+func TestServer_CreateCluster(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	vpcId := s.AddVpc(Vpc{Name: "testing"})
+	token := login(t, s)
+
+	if status := call(t, s, token, http.MethodPost, "/voks/v1/clusters", createClusterRequest{
+		Name:      "testing",
+		Version:   "v1.30.5",
+		VpcConfig: VpcConfig{VpcId: vpcId + 1},
+	}, nil); status != http.StatusBadRequest {
+		t.Errorf("expected an unknown VPC to be rejected, got %d", status)
+	}
+
+	var created clusterResponse
+	if status := call(t, s, token, http.MethodPost, "/voks/v1/clusters", createClusterRequest{
+		Name:          "testing",
+		Version:       "v1.30.5",
+		VpcConfig:     VpcConfig{VpcId: vpcId, SubnetIds: []int32{7281}},
+		EnableNfs:     true,
+		AddOnsStorage: 20,
+	}, &created); status != http.StatusOK {
+		t.Fatalf("expected cluster to be created, got %d", status)
+	}
+
+	clusterPath := "/voks/v1/clusters/" + itoa(created.Id)
+	for _, want := range []string{ClusterStatusCreating, ClusterStatusSuccess} {
+		var detail Cluster
+		call(t, s, token, http.MethodGet, clusterPath, nil, &detail)
+		if detail.Status != want {
+			t.Errorf("expected cluster status %q, got %q", want, detail.Status)
+		}
+		if detail.ControlPlaneResourceType != DefaultControlPlaneResourceType {
+			t.Errorf("expected the default control plane, got %q", detail.ControlPlaneResourceType)
+		}
+	}
+
+	var nfs Nfs
+	call(t, s, token, http.MethodPost, "/voks/v1/nfs/detail", clusterRequest{ClusterId: created.Id}, &nfs)
+	if nfs.StorageSize != NfsBaseStorage+20 {
+		t.Errorf("expected the additional storage to be allocated, got %v", nfs.StorageSize)
+	}
+
+	if status := call(t, s, token, http.MethodPost, "/voks/v1/clusters", createClusterRequest{
+		Name:      "testing",
+		Version:   "v1.30.5",
+		VpcConfig: VpcConfig{VpcId: vpcId},
+	}, nil); status != http.StatusConflict {
+		t.Errorf("expected a duplicate name to be rejected, got %d", status)
+	}
+}
+
+func TestServer_DeleteCluster(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	clusterId := s.AddCluster(Cluster{Name: "testing", Version: "v1.30.5", Nfs: &Nfs{StorageSize: 100}})
+	nodeGroupId := s.AddNodeGroup(NodeGroup{ClusterId: clusterId, Name: "workers"})
+	s.AddAddon(Addon{Name: "dashboard", KubernetesVersion: "v1.30.5", Versions: []string{"6.0.8"}})
+	s.InstallAddon(clusterId, "dashboard", "6.0.8")
+	token := login(t, s)
+
+	clusterPath := "/voks/v1/clusters/" + itoa(clusterId)
+	var nodeGroups []NodeGroup
+	call(t, s, token, http.MethodGet, clusterPath+"/node-groups", nil, &nodeGroups)
+	if len(nodeGroups) != 1 || nodeGroups[0].Id != nodeGroupId {
+		t.Errorf("expected the node group to be listed, got %+v", nodeGroups)
+	}
+	var addons []addonResponse
+	call(t, s, token, http.MethodGet, clusterPath+"/addons", nil, &addons)
+	if len(addons) != 1 || addons[0].Name != "dashboard" {
+		t.Errorf("expected the installed addon to be listed, got %+v", addons)
+	}
+
+	if status := call(t, s, token, http.MethodDelete, "/voks/v1/clusters", clusterRequest{ClusterId: clusterId}, nil); status != http.StatusConflict {
+		t.Errorf("expected a cluster with node groups to be kept, got %d", status)
+	}
+	call(t, s, token, http.MethodDelete, "/voks/v1/node-groups", nodeGroupRequest{ClusterId: clusterId, Id: nodeGroupId}, nil)
+	for i := 0; i < 2; i++ {
+		call(t, s, token, http.MethodGet, clusterPath, nil, nil)
+		call(t, s, token, http.MethodGet, clusterPath+"/node-groups", nil, nil)
+	}
+
+	if status := call(t, s, token, http.MethodPost, "/voks/v1/nfs/delete", clusterRequest{ClusterId: clusterId}, nil); status != http.StatusNoContent {
+		t.Fatalf("expected NFS storage to be deleted, got %d", status)
+	}
+	var nfs Nfs
+	call(t, s, token, http.MethodPost, "/voks/v1/nfs/detail", clusterRequest{ClusterId: clusterId}, &nfs)
+	if nfs.Status != NfsStatusDeleting {
+		t.Errorf("expected NFS storage to be deleting, got %q", nfs.Status)
+	}
+	if status := call(t, s, token, http.MethodPost, "/voks/v1/nfs/detail", clusterRequest{ClusterId: clusterId}, nil); status != http.StatusNotFound {
+		t.Errorf("expected deleted NFS storage to be gone, got %d", status)
+	}
+
+	if status := call(t, s, token, http.MethodDelete, "/voks/v1/clusters", clusterRequest{ClusterId: clusterId}, nil); status != http.StatusNoContent {
+		t.Fatalf("expected cluster to be deleted, got %d", status)
+	}
+	// The deletion is queued, the cluster is reported as it was once more
+	for _, want := range []string{ClusterStatusSuccess, ClusterStatusDeleting} {
+		var detail Cluster
+		call(t, s, token, http.MethodGet, clusterPath, nil, &detail)
+		if detail.Status != want {
+			t.Errorf("expected cluster status %q, got %q", want, detail.Status)
+		}
+	}
+	if status := call(t, s, token, http.MethodGet, clusterPath, nil, nil); status != http.StatusNotFound {
+		t.Errorf("expected deleted cluster to be gone, got %d", status)
+	}
+	if _, ok := s.Cluster(clusterId); ok {
+		t.Error("expected deleted cluster to be removed")
+	}
+}
+
+func TestServer_UpgradeCluster(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	clusterId := s.AddCluster(Cluster{Name: "testing", Version: "v1.29.8"})
+	nodeGroupId := s.AddNodeGroup(NodeGroup{ClusterId: clusterId, Name: "workers"})
+	token := login(t, s)
+
+	for _, version := range []string{"v1.29.8", "v1.28.9", "v1.31.0", "1.30"} {
+		if status := call(t, s, token, http.MethodPost, "/voks/v1/clusters/upgrade", upgradeClusterRequest{
+			ClusterId: clusterId,
+			Version:   version,
+		}, nil); status != http.StatusBadRequest {
+			t.Errorf("expected an upgrade to %s to be rejected, got %d", version, status)
+		}
+	}
+
+	if status := call(t, s, token, http.MethodPost, "/voks/v1/clusters/upgrade", upgradeClusterRequest{
+		ClusterId: clusterId,
+		Version:   "v1.30.5",
+	}, nil); status != http.StatusNoContent {
+		t.Fatalf("expected cluster to be upgraded, got %d", status)
+	}
+	clusterPath := "/voks/v1/clusters/" + itoa(clusterId)
+	// The upgrade is queued, and the new version only reported once done
+	for _, want := range []Cluster{
+		{Status: ClusterStatusSuccess, Version: "v1.29.8"},
+		{Status: ClusterStatusUpgrading, Version: "v1.29.8"},
+		{Status: ClusterStatusSuccess, Version: "v1.30.5"},
+	} {
+		var detail Cluster
+		call(t, s, token, http.MethodGet, clusterPath, nil, &detail)
+		if detail.Status != want.Status || detail.Version != want.Version {
+			t.Errorf("expected cluster %q at %s, got %q at %s", want.Status, want.Version, detail.Status, detail.Version)
+		}
+	}
+
+	if status := call(t, s, token, http.MethodPost, "/voks/v1/node-groups/upgrade", nodeGroupRequest{
+		ClusterId: clusterId,
+		Id:        nodeGroupId,
+	}, nil); status != http.StatusNoContent {
+		t.Fatalf("expected node group to be upgraded, got %d", status)
+	}
+	var detail NodeGroup
+	call(t, s, token, http.MethodGet, clusterPath+"/node-groups/"+itoa(nodeGroupId), nil, &detail)
+	if detail.Status != NodeGroupStatusUpdating {
+		t.Errorf("expected node group to be updating, got %q", detail.Status)
+	}
+}
+
+func TestServer_NodeGroupLifecycle(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	clusterId := s.AddCluster(Cluster{Name: "testing", Version: "v1.30.5"})
+	token := login(t, s)
+
+	var created nodeGroupResponse
+	if status := call(t, s, token, http.MethodPost, "/voks/v1/node-groups", createNodeGroupRequest{
+		ClusterId:    clusterId,
+		Name:         "workers",
+		ResourceType: "T1.vOKS 1",
+		MinNode:      1,
+		MaxNode:      1,
+	}, &created); status != http.StatusOK {
+		t.Fatalf("expected node group to be created, got %d", status)
+	}
+
+	detailPath := "/voks/v1/clusters/" + itoa(clusterId) + "/node-groups/" + itoa(created.Id)
+	wantStatuses := []string{NodeGroupStatusCreating, NodeGroupStatusSuccess, NodeGroupStatusSuccess}
+	for _, want := range wantStatuses {
+		var detail NodeGroup
+		call(t, s, token, http.MethodGet, detailPath, nil, &detail)
+		if detail.Status != want {
+			t.Errorf("expected status %q, got %q", want, detail.Status)
+		}
+	}
+
+	clusterPath := "/voks/v1/clusters/" + itoa(clusterId)
+	if status := call(t, s, token, http.MethodDelete, "/voks/v1/node-groups", nodeGroupRequest{
+		ClusterId: clusterId,
+		Id:        created.Id,
+	}, nil); status != http.StatusConflict {
+		t.Errorf("expected a change of the updating cluster to be rejected, got %d", status)
+	}
+	for _, want := range []string{ClusterStatusUpdating, ClusterStatusSuccess} {
+		var detail Cluster
+		call(t, s, token, http.MethodGet, clusterPath, nil, &detail)
+		if detail.Status != want {
+			t.Errorf("expected cluster status %q, got %q", want, detail.Status)
+		}
+	}
+
+	if status := call(t, s, token, http.MethodPut, "/voks/v1/node-groups", updateNodeGroupRequest{
+		ClusterId: clusterId,
+		Id:        created.Id,
+		MinNode:   3,
+		MaxNode:   1,
+	}, nil); status != http.StatusBadRequest {
+		t.Errorf("expected invalid scaling to be rejected, got %d", status)
+	}
+
+	if status := call(t, s, token, http.MethodDelete, "/voks/v1/node-groups", nodeGroupRequest{
+		ClusterId: clusterId,
+		Id:        created.Id,
+	}, nil); status != http.StatusNoContent {
+		t.Fatalf("expected node group to be deleted, got %d", status)
+	}
+	var deleting NodeGroup
+	call(t, s, token, http.MethodGet, detailPath, nil, &deleting)
+	if deleting.Status != NodeGroupStatusDeleting {
+		t.Errorf("expected node group to be deleting, got %q", deleting.Status)
+	}
+	if status := call(t, s, token, http.MethodGet, detailPath, nil, nil); status != http.StatusNotFound {
+		t.Errorf("expected deleted node group to be gone, got %d", status)
+	}
+
+	var detail Cluster
+	call(t, s, token, http.MethodGet, clusterPath, nil, &detail)
+	if detail.Status != ClusterStatusUpdating {
+		t.Errorf("expected cluster to be updating after the deletion, got %q", detail.Status)
+	}
+}
+
+func TestServer_AddonLifecycle(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.TransitionReads = 0
+	clusterId := s.AddCluster(Cluster{Name: "testing", Version: "v1.30.5"})
+	s.AddAddon(Addon{Name: "grafana", KubernetesVersion: "v1.30.5", Versions: []string{"8.0.2"}})
+	token := login(t, s)
+
+	detailPath := "/voks/v1/clusters/" + itoa(clusterId) + "/addons/grafana"
+	assertAddon := func(wantStatus, wantVersion string) {
+		t.Helper()
+
+		var detail addonResponse
+		call(t, s, token, http.MethodGet, detailPath, nil, &detail)
+		if detail.Status != wantStatus || detail.Version != wantVersion {
+			t.Errorf("expected addon %s %q, got %s %q", wantStatus, wantVersion, detail.Status, detail.Version)
+		}
+	}
+
+	assertAddon(AddonStatusInactive, "")
+
+	if status := call(t, s, token, http.MethodPost, "/voks/v1/addons/install", addonRequest{
+		ClusterId: clusterId,
+		Name:      "grafana",
+		Version:   "1.0.0",
+	}, nil); status != http.StatusBadRequest {
+		t.Errorf("expected unknown version to be rejected, got %d", status)
+	}
+
+	call(t, s, token, http.MethodPost, "/voks/v1/addons/install", addonRequest{
+		ClusterId: clusterId,
+		Name:      "grafana",
+		Version:   "8.0.2",
+	}, nil)
+	assertAddon(AddonStatusActive, "8.0.2")
+
+	call(t, s, token, http.MethodPost, "/voks/v1/addons/uninstall", addonRequest{
+		ClusterId: clusterId,
+		Name:      "grafana",
+	}, nil)
+	assertAddon(AddonStatusInactive, "")
+
+	if status := call(t, s, token, http.MethodPost, "/voks/v1/addons/uninstall", addonRequest{
+		ClusterId: clusterId,
+		Name:      "grafana",
+	}, nil); status != http.StatusNotFound {
+		t.Errorf("expected uninstalled addon to be not found, got %d", status)
+	}
+}
+
+func TestServer_FailNext(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.AddVpc(Vpc{Id: 1, Name: "default"}, Quota{Name: "cpu", Unit: "core", Value: 10})
+	token := login(t, s)
+
+	s.FailNext("VpcGetQuotaLimit", http.StatusServiceUnavailable, "MAINTENANCE", "Try again later")
+
+	if status := call(t, s, token, http.MethodPost, "/vpc/v1/vpcs/quota-limit", vpcQuotaRequest{VpcId: 1}, nil); status != http.StatusServiceUnavailable {
+		t.Errorf("expected injected failure, got %d", status)
+	}
+	var quotas itemsResponse[Quota]
+	if status := call(t, s, token, http.MethodPost, "/vpc/v1/vpcs/quota-limit", vpcQuotaRequest{VpcId: 1}, &quotas); status != http.StatusOK {
+		t.Errorf("expected the failure to be injected once, got %d", status)
+	}
+	if len(quotas.Items) != 1 {
+		t.Errorf("expected 1 quota, got %+v", quotas.Items)
+	}
+	if got := s.Calls("VpcGetQuotaLimit"); got != 2 {
+		t.Errorf("expected 2 calls, got %d", got)
+	}
+}
+
+func TestServer_ProviderConfig(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	if config := s.ProviderConfig(); !strings.Contains(config, `host      = "`+s.URL+`"`) {
+		t.Errorf("expected provider config to target the server, got:\n%s", config)
+	}
+}
+
+func itoa(i int32) string {
+	return strconv.Itoa(int(i))
+}
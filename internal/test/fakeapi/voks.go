@@ -0,0 +1,710 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fakeapi
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+)
+
+// Sizes of the clusters created through the API.
+const (
+	DefaultControlPlaneResourceType = "T1.vOKS 2"
+
+	NfsCpuSize     = 2
+	NfsMemorySize  = 4
+	NfsBaseStorage = 100
+)
+
+type createClusterRequest struct {
+	Name                     string    `json:"name"`
+	Version                  string    `json:"version"`
+	VpcConfig                VpcConfig `json:"vpcConfig"`
+	ControlPlaneResourceType string    `json:"controlPlaneResourceType"`
+	EnableNfs                bool      `json:"enableNfs"`
+	AddOnsStorage            int32     `json:"addOnsStorage"`
+}
+
+type clusterResponse struct {
+	Id int32 `json:"id"`
+}
+
+type upgradeClusterRequest struct {
+	ClusterId int32  `json:"clusterId"`
+	Version   string `json:"version"`
+}
+
+type clusterRequest struct {
+	ClusterId int32 `json:"clusterId"`
+}
+
+type kubeconfigResponse struct {
+	KubeConfig string `json:"kubeConfig"`
+}
+
+type extendNfsRequest struct {
+	ClusterId     int32 `json:"clusterId"`
+	AddOnsStorage int32 `json:"addOnsStorage"`
+}
+
+type createNodeGroupRequest struct {
+	ClusterId    int32   `json:"clusterId"`
+	Name         string  `json:"name"`
+	ResourceType string  `json:"resourceType"`
+	IsAutoScale  bool    `json:"isAutoScale"`
+	MinNode      int32   `json:"minNode"`
+	MaxNode      int32   `json:"maxNode"`
+	Labels       []Label `json:"labels"`
+	Taints       []Taint `json:"taints"`
+}
+
+type updateNodeGroupRequest struct {
+	ClusterId    int32  `json:"clusterId"`
+	Id           int32  `json:"id"`
+	Name         string `json:"name"`
+	IsAutoRepair bool   `json:"isAutoRepair"`
+	IsAutoScale  bool   `json:"isAutoScale"`
+	MinNode      int32  `json:"minNode"`
+	MaxNode      int32  `json:"maxNode"`
+}
+
+type nodeGroupRequest struct {
+	ClusterId int32 `json:"clusterId"`
+	Id        int32 `json:"id"`
+}
+
+type nodeGroupResponse struct {
+	ClusterId int32 `json:"clusterId"`
+	Id        int32 `json:"id"`
+}
+
+type addonRequest struct {
+	ClusterId int32  `json:"clusterId"`
+	Name      string `json:"name"`
+	Version   string `json:"version,omitempty"`
+}
+
+type addonResponse struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Version string `json:"version"`
+}
+
+type addonNameResponse struct {
+	AddOnName string `json:"addOnName"`
+}
+
+type addonVersionResponse struct {
+	VersionName string `json:"versionName"`
+}
+
+// cluster returns the cluster or a not found error.
+func (s *Server) cluster(id int32) (*cluster, error) {
+	c, ok := s.clusters[id]
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "CLUSTER_NOT_FOUND", "Cluster %d not found", id)
+	}
+	return c, nil
+}
+
+// settle moves the cluster through the updating status, as the API does
+// while it reconciles a change of its node groups or storage. A cluster in
+// error stays in error.
+func (s *Server) settle(c *cluster) {
+	settled := ClusterStatusSuccess
+	if c.status.current == ClusterStatusError {
+		settled = ClusterStatusError
+	}
+	c.status.transition(ClusterStatusUpdating, settled, s.TransitionReads)
+}
+
+// checkIdle rejects an operation on a cluster being changed, as the API does
+// for concurrent operations on one cluster.
+func checkIdle(c *cluster) error {
+	if c.status.pending() {
+		return errorf(http.StatusConflict, "CLUSTER_BUSY", "Cluster %d is %s", c.Id, c.status.current)
+	}
+	return nil
+}
+
+func (s *Server) createCluster(r *http.Request) (any, error) {
+	var body createClusterRequest
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	switch {
+	case body.Name == "":
+		return nil, invalidField("name", "must not be empty")
+	case body.Version == "":
+		return nil, invalidField("version", "must not be empty")
+	case body.EnableNfs && body.AddOnsStorage != 0 && (body.AddOnsStorage < 10 || body.AddOnsStorage > 2000):
+		return nil, invalidField("addOnsStorage", "must be between 10 and 2000")
+	}
+	if _, ok := s.vpcs[body.VpcConfig.VpcId]; !ok {
+		return nil, invalidField("vpcConfig.vpcId", fmt.Sprintf("VPC %d not found", body.VpcConfig.VpcId))
+	}
+	for _, c := range s.clusters {
+		if c.Name == body.Name {
+			return nil, errorf(http.StatusConflict, "CLUSTER_EXISTS", "Cluster %q already exists", body.Name)
+		}
+	}
+
+	id := s.id()
+	c := &cluster{
+		Cluster: Cluster{
+			Id:                       id,
+			Name:                     body.Name,
+			Version:                  body.Version,
+			ApiAddress:               fmt.Sprintf("https://10.10.%d.%d:6443", id/256%256, id%256),
+			ControlPlaneResourceType: body.ControlPlaneResourceType,
+			VpcConfig: VpcConfig{
+				VpcId:            body.VpcConfig.VpcId,
+				SecurityGroupIds: slices.Clone(body.VpcConfig.SecurityGroupIds),
+				SubnetIds:        slices.Clone(body.VpcConfig.SubnetIds),
+			},
+		},
+	}
+	if c.ControlPlaneResourceType == "" {
+		c.ControlPlaneResourceType = DefaultControlPlaneResourceType
+	}
+	c.status.transition(ClusterStatusCreating, ClusterStatusSuccess, s.TransitionReads)
+	if body.EnableNfs {
+		c.Nfs = &Nfs{
+			CpuSize:     NfsCpuSize,
+			MemorySize:  NfsMemorySize,
+			StorageSize: NfsBaseStorage + float64(body.AddOnsStorage),
+			InternalIp:  fmt.Sprintf("10.20.%d.%d", id/256%256, id%256),
+		}
+		c.nfsBaseStorage = NfsBaseStorage
+		c.nfsExtraStorage = body.AddOnsStorage
+		c.nfsStatus.transition(NfsStatusUpdating, NfsStatusPoweredOn, s.TransitionReads)
+	}
+	s.clusters[id] = c
+
+	return clusterResponse{Id: id}, nil
+}
+
+func (s *Server) detailCluster(r *http.Request) (any, error) {
+	id, err := pathId(r, "id")
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.cluster(id)
+	if err != nil {
+		return nil, err
+	}
+
+	status := c.status.read()
+	if !c.status.pending() && c.upgradeVersion != "" {
+		c.Version = c.upgradeVersion
+		c.upgradeVersion = ""
+	}
+	res := c.Cluster
+	res.Status = status
+	if res.Status == statusDeleted {
+		s.removeCluster(c.Id)
+		return nil, errorf(http.StatusNotFound, "CLUSTER_NOT_FOUND", "Cluster %d not found", id)
+	}
+	return res, nil
+}
+
+func (s *Server) upgradeCluster(r *http.Request) (any, error) {
+	var body upgradeClusterRequest
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	c, err := s.cluster(body.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkIdle(c); err != nil {
+		return nil, err
+	}
+
+	var major, minor, patch, targetMajor, targetMinor, targetPatch int
+	if _, err := fmt.Sscanf(c.Version, "v%d.%d.%d", &major, &minor, &patch); err != nil {
+		return nil, errorf(http.StatusInternalServerError, "INTERNAL_ERROR", "Cluster %d has an invalid version %q", c.Id, c.Version)
+	}
+	if _, err := fmt.Sscanf(body.Version, "v%d.%d.%d", &targetMajor, &targetMinor, &targetPatch); err != nil {
+		return nil, invalidField("version", "must be a version such as v1.30.5")
+	}
+	switch {
+	case targetMajor != major || targetMinor < minor || (targetMinor == minor && targetPatch <= patch):
+		return nil, invalidField("version", fmt.Sprintf("must be newer than %s", c.Version))
+	case targetMinor > minor+1:
+		return nil, invalidField("version", fmt.Sprintf("must not skip a minor version of %s", c.Version))
+	}
+
+	c.upgradeVersion = body.Version
+	c.status.queue(ClusterStatusUpgrading, ClusterStatusSuccess, s.TransitionReads)
+	return nil, nil
+}
+
+func (s *Server) deleteCluster(r *http.Request) (any, error) {
+	var body clusterRequest
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	c, err := s.cluster(body.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkIdle(c); err != nil {
+		return nil, err
+	}
+	for _, ng := range s.nodeGroups {
+		if ng.ClusterId == c.Id {
+			return nil, errorf(http.StatusConflict, "CLUSTER_NOT_EMPTY", "Cluster %d still has node groups", c.Id)
+		}
+	}
+
+	c.status.queue(ClusterStatusDeleting, statusDeleted, s.TransitionReads)
+	return nil, nil
+}
+
+// removeCluster removes a deleted cluster with its NFS storage and addons.
+func (s *Server) removeCluster(id int32) {
+	delete(s.clusters, id)
+	delete(s.addons, id)
+}
+
+func (s *Server) kubeconfig(r *http.Request) (any, error) {
+	var body clusterRequest
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	c, err := s.cluster(body.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeconfig := c.Kubeconfig
+	if kubeconfig == "" {
+		kubeconfig = fmt.Sprintf(`apiVersion: v1
+clusters:
+- cluster:
+    server: %[2]s
+  name: %[1]s
+contexts:
+- context:
+    cluster: %[1]s
+    user: %[1]s-admin
+  name: %[1]s-admin@%[1]s
+current-context: %[1]s-admin@%[1]s
+kind: Config
+preferences: {}
+users:
+- name: %[1]s-admin
+  user:
+    token: fake
+`, c.Name, c.ApiAddress)
+	}
+	return kubeconfigResponse{KubeConfig: kubeconfig}, nil
+}
+
+func (s *Server) detailNfs(r *http.Request) (any, error) {
+	var body clusterRequest
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	c, err := s.cluster(body.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	if c.Nfs == nil {
+		return nil, errorf(http.StatusNotFound, "NFS_NOT_FOUND", "Cluster %d has no NFS storage", c.Id)
+	}
+
+	status := c.nfsStatus.read()
+	if !c.nfsStatus.pending() {
+		// The storage is extended once the NFS storage powers on again
+		c.Nfs.StorageSize = c.nfsBaseStorage + float64(c.nfsExtraStorage)
+	}
+	res := *c.Nfs
+	res.Status = status
+	if res.Status == statusDeleted {
+		c.Nfs = nil
+		return nil, errorf(http.StatusNotFound, "NFS_NOT_FOUND", "Cluster %d has no NFS storage", c.Id)
+	}
+	return res, nil
+}
+
+func (s *Server) deleteNfs(r *http.Request) (any, error) {
+	var body clusterRequest
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	c, err := s.cluster(body.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkIdle(c); err != nil {
+		return nil, err
+	}
+	if c.Nfs == nil {
+		return nil, errorf(http.StatusNotFound, "NFS_NOT_FOUND", "Cluster %d has no NFS storage", c.Id)
+	}
+	if c.nfsStatus.pending() {
+		return nil, errorf(http.StatusConflict, "NFS_BUSY", "NFS storage of Cluster %d is being updated", c.Id)
+	}
+
+	c.nfsStatus.transition(NfsStatusDeleting, statusDeleted, s.TransitionReads)
+	return nil, nil
+}
+
+func (s *Server) extendNfs(r *http.Request) (any, error) {
+	var body extendNfsRequest
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	c, err := s.cluster(body.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkIdle(c); err != nil {
+		return nil, err
+	}
+	if c.Nfs == nil {
+		return nil, errorf(http.StatusNotFound, "NFS_NOT_FOUND", "Cluster %d has no NFS storage", c.Id)
+	}
+	if c.nfsStatus.pending() {
+		return nil, errorf(http.StatusConflict, "NFS_BUSY", "NFS storage of Cluster %d is being updated", c.Id)
+	}
+	if body.AddOnsStorage < 10 || body.AddOnsStorage > 2000 {
+		return nil, invalidField("addOnsStorage", "must be between 10 and 2000")
+	}
+	if body.AddOnsStorage < c.nfsExtraStorage {
+		return nil, invalidField("addOnsStorage", "must not be less than the current additional storage")
+	}
+
+	c.nfsExtraStorage = body.AddOnsStorage
+	c.nfsStatus.queue(NfsStatusUpdating, NfsStatusPoweredOn, s.TransitionReads)
+	return nil, nil
+}
+
+// nodeGroup returns the node group of the cluster or a not found error.
+func (s *Server) nodeGroup(clusterId, id int32) (*nodeGroup, error) {
+	ng, ok := s.nodeGroups[id]
+	if !ok || ng.ClusterId != clusterId {
+		return nil, errorf(http.StatusNotFound, "NODE_GROUP_NOT_FOUND", "Node group %d not found in Cluster %d", id, clusterId)
+	}
+	return ng, nil
+}
+
+func (s *Server) createNodeGroup(r *http.Request) (any, error) {
+	var body createNodeGroupRequest
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	c, err := s.cluster(body.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkIdle(c); err != nil {
+		return nil, err
+	}
+	switch {
+	case body.Name == "":
+		return nil, invalidField("name", "must not be empty")
+	case body.ResourceType == "":
+		return nil, invalidField("resourceType", "must not be empty")
+	case body.MinNode < 1:
+		return nil, invalidField("minNode", "must be at least 1")
+	case body.MaxNode < body.MinNode:
+		return nil, invalidField("maxNode", "must not be less than minNode")
+	}
+	for _, ng := range s.nodeGroups {
+		if ng.ClusterId == c.Id && ng.Name == body.Name {
+			return nil, errorf(http.StatusConflict, "NODE_GROUP_EXISTS", "Node group %q already exists in Cluster %d", body.Name, c.Id)
+		}
+	}
+
+	ng := &nodeGroup{
+		NodeGroup: NodeGroup{
+			Id:           s.id(),
+			ClusterId:    c.Id,
+			Name:         body.Name,
+			ResourceType: body.ResourceType,
+			IsAutoScale:  body.IsAutoScale,
+			MinNode:      body.MinNode,
+			MaxNode:      body.MaxNode,
+			Labels:       slices.Clone(body.Labels),
+			Taints:       slices.Clone(body.Taints),
+		},
+	}
+	ng.status.transition(NodeGroupStatusCreating, NodeGroupStatusSuccess, s.TransitionReads)
+	s.nodeGroups[ng.Id] = ng
+	s.settle(c)
+
+	return nodeGroupResponse{ClusterId: c.Id, Id: ng.Id}, nil
+}
+
+func (s *Server) updateNodeGroup(r *http.Request) (any, error) {
+	var body updateNodeGroupRequest
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	c, err := s.cluster(body.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkIdle(c); err != nil {
+		return nil, err
+	}
+	ng, err := s.nodeGroup(c.Id, body.Id)
+	if err != nil {
+		return nil, err
+	}
+	if ng.status.pending() {
+		return nil, errorf(http.StatusConflict, "NODE_GROUP_BUSY", "Node group %d is being %s", ng.Id, ng.status.current)
+	}
+	switch {
+	case body.MinNode < 1:
+		return nil, invalidField("minNode", "must be at least 1")
+	case body.MaxNode < body.MinNode:
+		return nil, invalidField("maxNode", "must not be less than minNode")
+	}
+
+	ng.IsAutoRepair = body.IsAutoRepair
+	ng.IsAutoScale = body.IsAutoScale
+	ng.MinNode = body.MinNode
+	ng.MaxNode = body.MaxNode
+	ng.status.transition(NodeGroupStatusUpdating, NodeGroupStatusSuccess, s.TransitionReads)
+	s.settle(c)
+
+	return nodeGroupResponse{ClusterId: c.Id, Id: ng.Id}, nil
+}
+
+func (s *Server) deleteNodeGroup(r *http.Request) (any, error) {
+	var body nodeGroupRequest
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	c, err := s.cluster(body.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkIdle(c); err != nil {
+		return nil, err
+	}
+	ng, err := s.nodeGroup(c.Id, body.Id)
+	if err != nil {
+		return nil, err
+	}
+	if ng.status.pending() {
+		return nil, errorf(http.StatusConflict, "NODE_GROUP_BUSY", "Node group %d is being %s", ng.Id, ng.status.current)
+	}
+
+	ng.status.transition(NodeGroupStatusDeleting, statusDeleted, s.TransitionReads)
+	s.settle(c)
+	return nil, nil
+}
+
+func (s *Server) upgradeNodeGroup(r *http.Request) (any, error) {
+	var body nodeGroupRequest
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	c, err := s.cluster(body.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkIdle(c); err != nil {
+		return nil, err
+	}
+	ng, err := s.nodeGroup(c.Id, body.Id)
+	if err != nil {
+		return nil, err
+	}
+	if ng.status.pending() {
+		return nil, errorf(http.StatusConflict, "NODE_GROUP_BUSY", "Node group %d is being %s", ng.Id, ng.status.current)
+	}
+
+	ng.status.transition(NodeGroupStatusUpdating, NodeGroupStatusSuccess, s.TransitionReads)
+	s.settle(c)
+	return nil, nil
+}
+
+func (s *Server) listNodeGroups(r *http.Request) (any, error) {
+	clusterId, err := pathId(r, "clusterId")
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.cluster(clusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []NodeGroup{}
+	for _, id := range slices.Sorted(maps.Keys(s.nodeGroups)) {
+		ng := s.nodeGroups[id]
+		if ng.ClusterId != c.Id {
+			continue
+		}
+		detail := ng.NodeGroup
+		detail.Status = ng.status.read()
+		if detail.Status == statusDeleted {
+			delete(s.nodeGroups, id)
+			continue
+		}
+		res = append(res, detail)
+	}
+	return res, nil
+}
+
+func (s *Server) detailNodeGroup(r *http.Request) (any, error) {
+	clusterId, err := pathId(r, "clusterId")
+	if err != nil {
+		return nil, err
+	}
+	id, err := pathId(r, "id")
+	if err != nil {
+		return nil, err
+	}
+	ng, err := s.nodeGroup(clusterId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := ng.NodeGroup
+	res.Status = ng.status.read()
+	if res.Status == statusDeleted {
+		delete(s.nodeGroups, id)
+		return nil, errorf(http.StatusNotFound, "NODE_GROUP_NOT_FOUND", "Node group %d not found in Cluster %d", id, clusterId)
+	}
+	return res, nil
+}
+
+func (s *Server) listAddons(r *http.Request) (any, error) {
+	kubernetesVersion := r.URL.Query().Get("k8sVersion")
+	name := r.URL.Query().Get("name")
+
+	res := []addonNameResponse{}
+	for _, addon := range s.catalog {
+		if addon.KubernetesVersion != kubernetesVersion || (name != "" && addon.Name != name) {
+			continue
+		}
+		res = append(res, addonNameResponse{AddOnName: addon.Name})
+	}
+	return res, nil
+}
+
+func (s *Server) listAddonVersions(r *http.Request) (any, error) {
+	addon, ok := s.addon(r.PathValue("name"), r.URL.Query().Get("k8sVersion"))
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "ADDON_NOT_FOUND", "Addon %q not found", r.PathValue("name"))
+	}
+	version := r.URL.Query().Get("version")
+
+	res := []addonVersionResponse{}
+	for _, v := range addon.Versions {
+		if version != "" && v != version {
+			continue
+		}
+		res = append(res, addonVersionResponse{VersionName: v})
+	}
+	return res, nil
+}
+
+func (s *Server) listInstalledAddons(r *http.Request) (any, error) {
+	clusterId, err := pathId(r, "clusterId")
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.cluster(clusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []addonResponse{}
+	for _, name := range slices.Sorted(maps.Keys(s.addons[c.Id])) {
+		installed := s.addons[c.Id][name]
+		status := installed.status.read()
+		if status == AddonStatusInactive {
+			continue
+		}
+		res = append(res, addonResponse{Name: name, Status: status, Version: installed.version})
+	}
+	return res, nil
+}
+
+func (s *Server) detailAddon(r *http.Request) (any, error) {
+	clusterId, err := pathId(r, "clusterId")
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.cluster(clusterId)
+	if err != nil {
+		return nil, err
+	}
+	name := r.PathValue("name")
+	if _, ok := s.addon(name, ""); !ok {
+		return nil, errorf(http.StatusNotFound, "ADDON_NOT_FOUND", "Addon %q not found", name)
+	}
+
+	installed, ok := s.addons[c.Id][name]
+	if !ok {
+		return addonResponse{Name: name, Status: AddonStatusInactive}, nil
+	}
+	return addonResponse{Name: name, Status: installed.status.read(), Version: installed.version}, nil
+}
+
+func (s *Server) installAddon(r *http.Request) (any, error) {
+	var body addonRequest
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	c, err := s.cluster(body.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkIdle(c); err != nil {
+		return nil, err
+	}
+	addon, ok := s.addon(body.Name, c.Version)
+	if !ok {
+		return nil, invalidField("name", fmt.Sprintf("addon is not available for Kubernetes %s", c.Version))
+	}
+	if !slices.Contains(addon.Versions, body.Version) {
+		return nil, invalidField("version", fmt.Sprintf("version is not available for Kubernetes %s", c.Version))
+	}
+	if installed, ok := s.addons[c.Id][body.Name]; ok && installed.status.current != AddonStatusInactive {
+		return nil, errorf(http.StatusConflict, "ADDON_INSTALLED", "Addon %q is %s on Cluster %d", body.Name, installed.status.current, c.Id)
+	}
+
+	if s.addons[c.Id] == nil {
+		s.addons[c.Id] = map[string]*installedAddon{}
+	}
+	installed := &installedAddon{version: body.Version}
+	installed.status.transition(AddonStatusInstalling, AddonStatusActive, s.TransitionReads)
+	s.addons[c.Id][body.Name] = installed
+	return nil, nil
+}
+
+func (s *Server) uninstallAddon(r *http.Request) (any, error) {
+	var body addonRequest
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	c, err := s.cluster(body.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkIdle(c); err != nil {
+		return nil, err
+	}
+	installed, ok := s.addons[c.Id][body.Name]
+	if !ok || installed.status.current == AddonStatusInactive {
+		return nil, errorf(http.StatusNotFound, "ADDON_NOT_INSTALLED", "Addon %q is not installed on Cluster %d", body.Name, c.Id)
+	}
+	if installed.status.pending() {
+		return nil, errorf(http.StatusConflict, "ADDON_BUSY", "Addon %q is %s on Cluster %d", body.Name, installed.status.current, c.Id)
+	}
+
+	installed.version = ""
+	installed.status.transition(AddonStatusUninstalling, AddonStatusInactive, s.TransitionReads)
+	return nil, nil
+}
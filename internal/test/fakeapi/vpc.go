@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fakeapi
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+)
+
+type vpcListRequest struct {
+	HostId int32 `json:"hostId"`
+	VpcId  int32 `json:"vpcId"`
+}
+
+type vpcDetailRequest struct {
+	Id    int32 `json:"id"`
+	VpcId int32 `json:"vpcId"`
+}
+
+type vpcQuotaRequest struct {
+	VpcId int32 `json:"vpcId"`
+}
+
+type itemsResponse[T any] struct {
+	Items []T `json:"items"`
+}
+
+func (s *Server) vpc(id int32) (*Vpc, error) {
+	vpc, ok := s.vpcs[id]
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "VPC_NOT_FOUND", "VPC %d not found", id)
+	}
+	return vpc, nil
+}
+
+func (s *Server) listVpcs(r *http.Request) (any, error) {
+	var body vpcListRequest
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+
+	res := itemsResponse[Vpc]{Items: []Vpc{}}
+	for _, id := range slices.Sorted(maps.Keys(s.vpcs)) {
+		if body.VpcId != 0 && id != body.VpcId {
+			continue
+		}
+		res.Items = append(res.Items, *s.vpcs[id])
+	}
+	return res, nil
+}
+
+func (s *Server) detailVpc(r *http.Request) (any, error) {
+	var body vpcDetailRequest
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	id := body.VpcId
+	if id == 0 {
+		id = body.Id
+	}
+	vpc, err := s.vpc(id)
+	if err != nil {
+		return nil, err
+	}
+	return *vpc, nil
+}
+
+func (s *Server) vpcQuotaLimit(r *http.Request) (any, error) {
+	var body vpcQuotaRequest
+	if err := decode(r, &body); err != nil {
+		return nil, err
+	}
+	if _, err := s.vpc(body.VpcId); err != nil {
+		return nil, err
+	}
+	return itemsResponse[Quota]{Items: append([]Quota{}, s.quotas[body.VpcId]...)}, nil
+}
@@ -5,46 +5,60 @@ package voks
 
 import (
 	"fmt"
+	"net/http"
+	"regexp"
 	"strconv"
+	"terraform-provider-viettelidc/internal/test/fakeapi"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
 func TestClusterResource(t *testing.T) {
 
 	var (
-		clusterId = 2459
-		name      = "k8s-idc-162e0ab7"
-		version   = "v1.30.5"
-		vpc_id    = 19178
+		name    = "tf-acc-cluster"
+		version = "v1.30.5"
+		vpc_id  = 19178
 	)
-
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
-			// ImportState testing
+			// The size of the NFS Storage is checked at plan time
+			{
+				Config:      providerConfig + testClusterResourceConfig(name, version, vpc_id, 5),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("between 10 and 2000"),
+			},
+			// Create and Read testing
 			{
-				Config: providerConfig + testClusterResourceConfig(name, version, vpc_id, 0),
+				Config: providerConfig + testClusterResourceConfig(name, version, vpc_id, 20),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					//Check resource attribute value with terraform state
-					resource.TestCheckResourceAttr("viettelidc_voks_cluster.testing", "nfs.total_storage_size", strconv.Itoa(100)),
+					resource.TestCheckResourceAttrSet("viettelidc_voks_cluster.testing", "id"),
+					resource.TestCheckResourceAttrSet("viettelidc_voks_cluster.testing", "endpoint"),
+					resource.TestCheckResourceAttrSet("viettelidc_voks_cluster.testing", "control_plane_resource_type"),
+					resource.TestCheckResourceAttr("viettelidc_voks_cluster.testing", "status", "SUCCESS"),
+					resource.TestCheckResourceAttr("viettelidc_voks_cluster.testing", "nfs.status", "POWERED_ON"),
+					resource.TestCheckResourceAttr("viettelidc_voks_cluster.testing", "nfs.total_storage_size", strconv.Itoa(120)),
 				),
 			},
+			// ImportState testing
 			{
-				Config:                               providerConfig + testClusterResourceConfig(name, version, vpc_id, 0),
+				Config:                               providerConfig + testClusterResourceConfig(name, version, vpc_id, 20),
 				ResourceName:                         "viettelidc_voks_cluster.testing",
 				ImportState:                          true,
 				ImportStateVerifyIdentifierAttribute: "id",
-				ImportStateId:                        strconv.Itoa(clusterId),
 			},
 			// Update and Read testing
 			{
 				Config: providerConfig + testClusterResourceConfig(name, version, vpc_id, 50),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					//Check resource attribute value with terraform state
-					resource.TestCheckResourceAttr("viettelidc_voks_cluster.testing", "nfs.total_storage_size", strconv.Itoa(100)),
+					resource.TestCheckResourceAttr("viettelidc_voks_cluster.testing", "nfs.total_storage_size", strconv.Itoa(150)),
 				),
 			},
 			// Delete testing automatically occurs in TestCase
@@ -52,6 +66,214 @@ func TestClusterResource(t *testing.T) {
 	})
 }
 
+func TestClusterResource_Upgrade(t *testing.T) {
+
+	var (
+		name   = "tf-acc-cluster-upgrade"
+		vpc_id = 19178
+	)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testClusterResourceConfig(name, "v1.29.8", vpc_id, 0),
+			},
+			// Skipping a minor version and downgrading are rejected at plan time
+			{
+				Config:      providerConfig + testClusterResourceConfig(name, "v1.31.0", vpc_id, 0),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("one minor version at a time"),
+			},
+			{
+				Config:      providerConfig + testClusterResourceConfig(name, "v1.28.9", vpc_id, 0),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`downgrades are not\s+supported`),
+			},
+			// Upgrade in place
+			{
+				Config: providerConfig + testClusterResourceConfig(name, "v1.30.5", vpc_id, 0),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("viettelidc_voks_cluster.testing", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("viettelidc_voks_cluster.testing", "version", "v1.30.5"),
+					resource.TestCheckResourceAttr("viettelidc_voks_cluster.testing", "status", "SUCCESS"),
+				),
+			},
+		},
+	})
+}
+
+func TestClusterResource_CreateFailed(t *testing.T) {
+	skipUnlessFake(t)
+
+	config := providerConfig + testClusterResourceConfig("tf-acc-cluster-create-failed", "v1.29.8", 19178, 0)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The Cluster is saved, without the values only known once it is
+			// ready, before the wait fails
+			{
+				PreConfig: func() {
+					fakeServer.FailNext("DetailCluster", http.StatusBadRequest, "CLUSTER_UNAVAILABLE", "Cluster is unavailable")
+				},
+				Config:      config,
+				ExpectError: regexp.MustCompile("CLUSTER_UNAVAILABLE"),
+			},
+			{
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("viettelidc_voks_cluster.testing", plancheck.ResourceActionReplace),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("viettelidc_voks_cluster.testing", "endpoint"),
+					resource.TestCheckResourceAttr("viettelidc_voks_cluster.testing", "status", "SUCCESS"),
+				),
+			},
+		},
+	})
+}
+
+func TestClusterResource_DeleteInError(t *testing.T) {
+	skipUnlessFake(t)
+
+	var clusterId int32
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(state *terraform.State) error {
+			if _, ok := fakeServer.Cluster(clusterId); ok {
+				return fmt.Errorf("cluster %d still exists", clusterId)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testClusterResourceConfig("tf-acc-cluster-error", "v1.29.8", 19178, 0) + `
+resource "viettelidc_voks_node_group" "testing" {
+	cluster_id = viettelidc_voks_cluster.testing.id
+	name = "tf-acc-node-group-error"
+	resource_type = "T1.vOKS 1"
+
+	scaling_config {
+		enable_auto_scale = false
+		min_node = 1
+		max_node = 1
+	}
+}`,
+				Check: func(state *terraform.State) error {
+					id, err := strconv.Atoi(state.RootModule().Resources["viettelidc_voks_cluster.testing"].Primary.ID)
+					if err != nil {
+						return err
+					}
+					clusterId = int32(id)
+					return nil
+				},
+			},
+			// The node group, then the cluster, are destroyed while the
+			// cluster is in error
+			{
+				PreConfig: func() {
+					fakeServer.SetClusterStatus(clusterId, fakeapi.ClusterStatusError)
+				},
+				Config: providerConfig,
+			},
+		},
+	})
+}
+
+func TestClusterResource_Delete(t *testing.T) {
+	skipUnlessFake(t)
+
+	var (
+		clusterId        int32
+		deleteNodeGroups int
+		uninstallAddons  int
+	)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(state *terraform.State) error {
+			if _, ok := fakeServer.Cluster(clusterId); ok {
+				return fmt.Errorf("cluster %d still exists", clusterId)
+			}
+			// force_delete removed the node group and the addon first
+			if calls := fakeServer.Calls("DeleteNodeGroup") - deleteNodeGroups; calls != 1 {
+				return fmt.Errorf("expected 1 DeleteNodeGroup call, got: %d", calls)
+			}
+			if calls := fakeServer.Calls("UninstallAddOn") - uninstallAddons; calls != 1 {
+				return fmt.Errorf("expected 1 UninstallAddOn call, got: %d", calls)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testClusterDeleteConfig(true, false),
+				Check: func(state *terraform.State) error {
+					id, err := strconv.Atoi(state.RootModule().Resources["viettelidc_voks_cluster.testing"].Primary.ID)
+					if err != nil {
+						return err
+					}
+					clusterId = int32(id)
+					return nil
+				},
+			},
+			{
+				Config:      providerConfig + testClusterDeleteConfig(true, false),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("`deletion_protection` is enabled"),
+			},
+			// A Cluster still having node groups is not deleted without
+			// force_delete
+			{
+				PreConfig: func() {
+					fakeServer.AddNodeGroup(fakeapi.NodeGroup{
+						ClusterId:    clusterId,
+						Name:         "tf-acc-unmanaged",
+						ResourceType: "T1.vOKS 1",
+						MinNode:      1,
+						MaxNode:      1,
+					})
+					fakeServer.InstallAddon(clusterId, "dashboard", "6.0.8")
+				},
+				Config: providerConfig + testClusterDeleteConfig(false, false),
+			},
+			{
+				Config:      providerConfig + testClusterDeleteConfig(false, false),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("CLUSTER_NOT_EMPTY"),
+			},
+			{
+				Config: providerConfig + testClusterDeleteConfig(false, true),
+				Check: func(state *terraform.State) error {
+					deleteNodeGroups = fakeServer.Calls("DeleteNodeGroup")
+					uninstallAddons = fakeServer.Calls("UninstallAddOn")
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testClusterDeleteConfig(deletionProtection, forceDelete bool) string {
+	return fmt.Sprintf(`
+resource "viettelidc_voks_cluster" "testing" {
+	name = "tf-acc-cluster-delete"
+	version = "v1.29.8"
+	deletion_protection = %t
+	force_delete = %t
+	vpc_config {
+		vpc_id = 19178
+	}
+}`, deletionProtection, forceDelete)
+}
+
 func testClusterResourceConfig(name, version string, vpcId, nfsAdditionalSize int) string {
 
 	var nfsConfig string
@@ -66,6 +288,8 @@ nfs = {
 resource "viettelidc_voks_cluster" "testing" {
 	name = "%s"
 	version = "%s"
+	deletion_protection = false
+	force_delete = true
 	vpc_config {
 		vpc_id = %d
 	}
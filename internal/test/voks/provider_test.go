@@ -4,7 +4,9 @@
 package voks
 
 import (
+	"os"
 	"terraform-provider-viettelidc/internal/provider"
+	"terraform-provider-viettelidc/internal/test/fakeapi"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
@@ -16,18 +18,103 @@ import (
 // CLI command executed to create a provider server to which the CLI can
 // reattach.
 var (
-	providerConfig = `
-provider "viettelidc" {
-  domain_id = "9e9480cc-96aa-446e-b08b-5cd7b2f438ab"
-  username = "test-iac"
-  password = "Vtdc@2024"
-}
-`
+	// providerConfig targets the fake API started by TestMain, or the real
+	// API when VIETTELIDC_ACC_LIVE is set.
+	providerConfig string
+	// fakeServer is the fake API started by TestMain, nil when testing
+	// against the real API.
+	fakeServer *fakeapi.Server
+
 	testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
 		"viettelidc": providerserver.NewProtocol6WithError(provider.New("test")()),
 	}
 )
 
+func TestMain(m *testing.M) {
+	if os.Getenv("VIETTELIDC_ACC_LIVE") != "" {
+		// Credentials are read from the VIETTELIDC_* environment variables.
+		providerConfig = `
+provider "viettelidc" {}
+`
+		os.Exit(m.Run())
+	}
+
+	fakeServer = newFakeServer()
+	providerConfig = fakeServer.ProviderConfig()
+	code := m.Run()
+	fakeServer.Close()
+	os.Exit(code)
+}
+
+// newFakeServer starts a fake API holding the objects the acceptance tests
+// expect to exist.
+func newFakeServer() *fakeapi.Server {
+	server := fakeapi.NewServer()
+
+	server.AddCluster(fakeapi.Cluster{
+		Id:         2459,
+		Name:       "k8s-idc-162e0ab7",
+		Version:    "v1.30.5",
+		ApiAddress: "https://172.17.11.221:6443",
+		VpcConfig:  fakeapi.VpcConfig{VpcId: 19178},
+		Nfs: &fakeapi.Nfs{
+			CpuSize:     2,
+			MemorySize:  2,
+			StorageSize: 100,
+			InternalIp:  "10.20.29.221",
+		},
+		Kubeconfig: testKubeconfig,
+	})
+	server.AddCluster(fakeapi.Cluster{
+		Id:         2477,
+		Name:       "k8s-idc-iac-test",
+		Version:    "v1.29.8",
+		ApiAddress: "https://172.17.11.60:6443",
+		VpcConfig:  fakeapi.VpcConfig{VpcId: 19178},
+	})
+	server.AddCluster(fakeapi.Cluster{
+		Id:         2555,
+		Name:       "k8s-idc-0de2cc72",
+		Version:    "v1.29.8",
+		ApiAddress: "https://172.17.11.53:6443",
+		VpcConfig:  fakeapi.VpcConfig{VpcId: 19178},
+		Nfs: &fakeapi.Nfs{
+			CpuSize:     2,
+			MemorySize:  2,
+			StorageSize: 100,
+			InternalIp:  "10.20.29.230",
+		},
+	})
+
+	server.AddAddon(fakeapi.Addon{
+		Name:              "dashboard",
+		KubernetesVersion: "v1.29.8",
+		Versions:          []string{"6.0.8", "6.0.7"},
+	})
+	server.AddAddon(fakeapi.Addon{
+		Name:              "grafana",
+		KubernetesVersion: "v1.29.8",
+		Versions:          []string{"8.0.2"},
+	})
+
+	server.AddVpc(fakeapi.Vpc{
+		Id:     19178,
+		Name:   "vpc-iac-test",
+		Status: "ACTIVE",
+	})
+
+	return server
+}
+
+// skipUnlessFake skips tests driving the fake API into states the real one
+// cannot be put in on demand.
+func skipUnlessFake(t *testing.T) {
+	t.Helper()
+	if fakeServer == nil {
+		t.Skip("requires the fake API")
+	}
+}
+
 func testAccPreCheck(t *testing.T) {
 	// You can add code here to run prior to any test case execution, for example assertions
 	// about the appropriate environment variables being set are common to see in a pre-check
@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package voks
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestRegionsDatasource(t *testing.T) {
+	skipUnlessFake(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testRegionsProviderConfig() + testRegionsDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.viettelidc_regions.testing", "current", "lab"),
+					resource.TestCheckResourceAttr("data.viettelidc_regions.testing", "items.#", "2"),
+					resource.TestCheckResourceAttr("data.viettelidc_regions.testing", "items.0.code", "lab"),
+					resource.TestCheckResourceAttr("data.viettelidc_regions.testing", "items.0.endpoint", fakeServer.URL),
+					resource.TestCheckResourceAttr("data.viettelidc_regions.testing", "items.0.host_id", "67890"),
+					resource.TestCheckResourceAttr("data.viettelidc_regions.testing", "items.1.code", "staging"),
+					resource.TestCheckNoResourceAttr("data.viettelidc_regions.testing", "items.1.host_id"),
+					resource.TestCheckResourceAttr("data.viettelidc_availability_zones.testing", "region", "lab"),
+					resource.TestCheckResourceAttr("data.viettelidc_availability_zones.testing", "items.#", "2"),
+					resource.TestCheckResourceAttr("data.viettelidc_availability_zones.testing", "items.0.code", "lab-1"),
+					resource.TestCheckResourceAttr("data.viettelidc_availability_zones.testing", "items.0.name", "Rack A"),
+					// The VPCs are listed from the API gateway and host of
+					// the region.
+					resource.TestCheckResourceAttr("data.viettelidc_vpcs.testing", "items.0.id", "19178"),
+				),
+			},
+		},
+	})
+}
+
+// testRegionsProviderConfig serves the region of the provider from the fake
+// API, which also serves the IAM API through its endpoint override.
+func testRegionsProviderConfig() string {
+	return fmt.Sprintf(`
+provider "viettelidc" {
+  region    = "lab"
+  domain_id = %q
+  username  = %q
+  password  = %q
+
+  regions = {
+    lab = {
+      name     = "Lab"
+      endpoint = %q
+      host_id  = 67890
+      zones = {
+        "lab-1" = "Rack A"
+        "lab-2" = "Rack B"
+      }
+    }
+    staging = {
+      endpoint = "https://staging.invalid"
+    }
+  }
+
+  endpoints {
+    iam = %q
+  }
+}
+`, fakeServer.DomainId, fakeServer.Username, fakeServer.Password, fakeServer.URL, fakeServer.URL)
+}
+
+const testRegionsDataSourceConfig = `
+data "viettelidc_regions" "testing" {}
+
+data "viettelidc_availability_zones" "testing" {}
+
+data "viettelidc_vpcs" "testing" {
+  vpc_id = 19178
+}
+`
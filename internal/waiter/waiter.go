@@ -0,0 +1,217 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package waiter polls long-running Viettelidc operations until the resource
+// reaches a target state.
+package waiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTimeout is how long a StateChangeConf waits unless configured
+	// otherwise.
+	DefaultTimeout = 20 * time.Minute
+	// DefaultMinInterval and DefaultMaxInterval bound the wait between two
+	// refreshes unless configured otherwise.
+	DefaultMinInterval = 5 * time.Second
+	DefaultMaxInterval = 30 * time.Second
+)
+
+// RefreshFunc reads the object being waited on and returns its current state.
+// Returning an error stops the wait.
+type RefreshFunc func(ctx context.Context) (state string, err error)
+
+// StateChangeConf describes how to wait for an object to reach one of the
+// Target states. States are compared case-insensitively.
+type StateChangeConf struct {
+	// Pending states are expected on the way to Target. When empty, every
+	// state not listed in Target or Failure is treated as pending.
+	Pending []string
+	// Target states end the wait successfully.
+	Target []string
+	// Failure states end the wait with a FailureStateError.
+	Failure []string
+
+	Refresh RefreshFunc
+
+	// Timeout bounds the whole wait, defaulting to DefaultTimeout.
+	Timeout time.Duration
+	// Delay is waited before the first refresh.
+	Delay time.Duration
+	// MinInterval and MaxInterval bound the exponentially growing, jittered
+	// wait between two refreshes.
+	MinInterval time.Duration
+	MaxInterval time.Duration
+	// ContinuousTargetOccurrence is the number of consecutive refreshes that
+	// must report a target state, defaulting to 1.
+	ContinuousTargetOccurrence int
+}
+
+// TimeoutError is returned when the object has not reached a target state
+// within the timeout.
+type TimeoutError struct {
+	LastState string
+	Target    []string
+	// Timeout is how long the object was waited on.
+	Timeout time.Duration
+	// Operation is set when the deadline of the caller's context, e.g. the
+	// timeout of the whole operation the wait is part of, expired before the
+	// timeout of the wait.
+	Operation bool
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Operation {
+		return fmt.Sprintf("operation timed out after waiting %s for state to become %s (last state: %q)",
+			e.Timeout, quoteStates(e.Target), e.LastState)
+	}
+	return fmt.Sprintf("timeout after %s while waiting for state to become %s (last state: %q)",
+		e.Timeout, quoteStates(e.Target), e.LastState)
+}
+
+// FailureStateError is returned when the object reaches a failure state.
+type FailureStateError struct {
+	State string
+}
+
+func (e *FailureStateError) Error() string {
+	return fmt.Sprintf("operation failed with state %q", e.State)
+}
+
+// UnexpectedStateError is returned when the object reaches a state that is
+// neither pending, target nor failure.
+type UnexpectedStateError struct {
+	State    string
+	Expected []string
+}
+
+func (e *UnexpectedStateError) Error() string {
+	return fmt.Sprintf("unexpected state %q, wanted one of %s", e.State, quoteStates(e.Expected))
+}
+
+// WaitForState refreshes the object until it reaches a target state, then
+// returns that state. It stops early when ctx is cancelled, the timeout
+// expires, Refresh fails or the object reaches a failure or unexpected state.
+func (c *StateChangeConf) WaitForState(ctx context.Context) (string, error) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	minInterval := c.MinInterval
+	if minInterval <= 0 {
+		minInterval = DefaultMinInterval
+	}
+	maxInterval := c.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = DefaultMaxInterval
+	}
+	maxInterval = max(maxInterval, minInterval)
+	occurrences := max(c.ContinuousTargetOccurrence, 1)
+
+	start := time.Now()
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var lastState string
+	wait := c.Delay
+	interval := minInterval
+	targetCount := 0
+
+	for {
+		if err := sleep(waitCtx, wait); err != nil {
+			return lastState, c.contextError(ctx, err, lastState, start, timeout)
+		}
+
+		state, err := c.Refresh(waitCtx)
+		if err != nil {
+			if waitCtx.Err() != nil {
+				return lastState, c.contextError(ctx, waitCtx.Err(), lastState, start, timeout)
+			}
+			return lastState, err
+		}
+		lastState = state
+
+		switch {
+		case containsState(c.Target, state):
+			targetCount++
+			if targetCount >= occurrences {
+				return state, nil
+			}
+			// Poll again shortly to confirm the state is stable.
+			wait = jitter(minInterval)
+			continue
+		case containsState(c.Failure, state):
+			return state, &FailureStateError{State: state}
+		case len(c.Pending) > 0 && !containsState(c.Pending, state):
+			return state, &UnexpectedStateError{
+				State:    state,
+				Expected: slices.Concat(c.Pending, c.Target),
+			}
+		}
+
+		targetCount = 0
+		wait = jitter(interval)
+		interval = min(interval*2, maxInterval)
+	}
+}
+
+// contextError tells a cancellation of the caller's context apart from the
+// expiry of the wait timeout, or of the deadline of the caller's context, e.g.
+// the timeout of the whole operation the wait is part of.
+func (c *StateChangeConf) contextError(ctx context.Context, err error, lastState string, start time.Time, timeout time.Duration) error {
+	if !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok && ctx.Err() != nil {
+		return &TimeoutError{
+			LastState: lastState,
+			Target:    c.Target,
+			Timeout:   deadline.Sub(start).Round(time.Millisecond),
+			Operation: true,
+		}
+	}
+	return &TimeoutError{LastState: lastState, Target: c.Target, Timeout: timeout}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func jitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	return d/2 + rand.N(d/2)
+}
+
+func containsState(states []string, state string) bool {
+	return slices.ContainsFunc(states, func(s string) bool {
+		return strings.EqualFold(s, state)
+	})
+}
+
+func quoteStates(states []string) string {
+	quoted := make([]string, len(states))
+	for i, s := range states {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+	return strings.Join(quoted, ", ")
+}
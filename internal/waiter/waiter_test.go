@@ -0,0 +1,178 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package waiter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWaitForState(t *testing.T) {
+	testCases := map[string]struct {
+		states          []string
+		pending         []string
+		occurrences     int
+		wantState       string
+		wantRefreshes   int
+		wantFailure     bool
+		wantUnexpected  bool
+		wantTimeout     bool
+		wantRefreshErr  bool
+		refreshErrAfter int
+	}{
+		"target": {
+			states:        []string{"pending", "pending", "Active"},
+			wantState:     "Active",
+			wantRefreshes: 3,
+		},
+		"continuous-target": {
+			states:        []string{"active", "pending", "active", "active"},
+			occurrences:   2,
+			wantState:     "active",
+			wantRefreshes: 4,
+		},
+		"failure": {
+			states:        []string{"pending", "error"},
+			wantState:     "error",
+			wantRefreshes: 2,
+			wantFailure:   true,
+		},
+		"unexpected": {
+			states:         []string{"pending", "failed"},
+			pending:        []string{"pending"},
+			wantState:      "failed",
+			wantRefreshes:  2,
+			wantUnexpected: true,
+		},
+		"any-pending": {
+			states:        []string{"creating", "scaling", "active"},
+			wantState:     "active",
+			wantRefreshes: 3,
+		},
+		"timeout": {
+			states:      []string{"pending"},
+			wantTimeout: true,
+		},
+		"refresh-error": {
+			states:          []string{"pending", "pending"},
+			refreshErrAfter: 2,
+			wantRefreshErr:  true,
+		},
+	}
+
+	refreshErr := errors.New("refresh failed")
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			refreshes := 0
+			conf := &StateChangeConf{
+				Pending: testCase.pending,
+				Target:  []string{"active"},
+				Failure: []string{"error"},
+				Refresh: func(ctx context.Context) (string, error) {
+					refreshes++
+					if testCase.refreshErrAfter > 0 && refreshes > testCase.refreshErrAfter {
+						return "", refreshErr
+					}
+					return testCase.states[min(refreshes, len(testCase.states))-1], nil
+				},
+				Timeout:                    100 * time.Millisecond,
+				MinInterval:                time.Millisecond,
+				MaxInterval:                2 * time.Millisecond,
+				ContinuousTargetOccurrence: testCase.occurrences,
+			}
+
+			state, err := conf.WaitForState(context.Background())
+
+			var failureErr *FailureStateError
+			var unexpectedErr *UnexpectedStateError
+			var timeoutErr *TimeoutError
+			switch {
+			case testCase.wantFailure:
+				if !errors.As(err, &failureErr) {
+					t.Fatalf("expected FailureStateError, got: %v", err)
+				}
+			case testCase.wantUnexpected:
+				if !errors.As(err, &unexpectedErr) {
+					t.Fatalf("expected UnexpectedStateError, got: %v", err)
+				}
+			case testCase.wantTimeout:
+				if !errors.As(err, &timeoutErr) {
+					t.Fatalf("expected TimeoutError, got: %v", err)
+				}
+				if timeoutErr.LastState != "pending" {
+					t.Errorf("expected last state pending, got: %q", timeoutErr.LastState)
+				}
+				if timeoutErr.Operation || timeoutErr.Timeout != conf.Timeout {
+					t.Errorf("expected the timeout of the wait to be reported, got: %s", timeoutErr)
+				}
+				return
+			case testCase.wantRefreshErr:
+				if !errors.Is(err, refreshErr) {
+					t.Fatalf("expected refresh error, got: %v", err)
+				}
+				return
+			default:
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+			}
+
+			if state != testCase.wantState {
+				t.Errorf("expected state %q, got: %q", testCase.wantState, state)
+			}
+			if refreshes != testCase.wantRefreshes {
+				t.Errorf("expected %d refreshes, got: %d", testCase.wantRefreshes, refreshes)
+			}
+		})
+	}
+}
+
+func TestWaitForStateCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	conf := &StateChangeConf{
+		Target: []string{"active"},
+		Refresh: func(ctx context.Context) (string, error) {
+			cancel()
+			return "pending", nil
+		},
+		MinInterval: time.Hour,
+	}
+
+	start := time.Now()
+	_, err := conf.WaitForState(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the wait to stop on cancellation, took %s", elapsed)
+	}
+}
+
+func TestWaitForStateCallerDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	conf := &StateChangeConf{
+		Target: []string{"active"},
+		Refresh: func(ctx context.Context) (string, error) {
+			return "pending", nil
+		},
+		Timeout:     time.Hour,
+		MinInterval: time.Millisecond,
+	}
+
+	_, err := conf.WaitForState(ctx)
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected a TimeoutError, got: %v", err)
+	}
+	if timeoutErr.LastState != "pending" {
+		t.Errorf("expected last state pending, got: %s", timeoutErr.LastState)
+	}
+	if !timeoutErr.Operation || timeoutErr.Timeout > time.Second {
+		t.Errorf("expected the deadline of the operation to be reported, got: %s", timeoutErr)
+	}
+}